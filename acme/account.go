@@ -0,0 +1,94 @@
+package acme
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"gopkg.in/square/go-jose.v2"
+)
+
+type newAccountRequest struct {
+	Contact              []string `json:"contact,omitempty"`
+	TermsOfServiceAgreed bool     `json:"termsOfServiceAgreed,omitempty"`
+	OnlyReturnExisting   bool     `json:"onlyReturnExisting,omitempty"`
+}
+
+// NewAccount serves POST /acme/new-account (RFC 8555 §7.3).
+func (s *Server) NewAccount(w http.ResponseWriter, req *http.Request) {
+	defer s.setNonce(w)
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", err.Error())
+		return
+	}
+
+	payload, header, jwk, err := verifyJWS(body)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", "invalid JWS: "+err.Error())
+		return
+	}
+	if !s.nonces.Redeem(header.Nonce) {
+		writeProblem(w, http.StatusBadRequest, "badNonce", "nonce is missing, unknown, or already used")
+		return
+	}
+	if header.URL != s.url("/acme/new-account") {
+		writeProblem(w, http.StatusBadRequest, "unauthorized", "JWS url claim does not match the request")
+		return
+	}
+	if jwk == nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", "new-account requires an embedded jwk")
+		return
+	}
+
+	var nar newAccountRequest
+	if len(payload) > 0 {
+		if err := unmarshalJSON(payload, &nar); err != nil {
+			writeProblem(w, http.StatusBadRequest, "malformed", err.Error())
+			return
+		}
+	}
+
+	thumb, err := thumbprint(jwk)
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+
+	if existing, err := s.Store.GetAccountByJWKThumbprint(thumb); err == nil {
+		writeJSON(w, http.StatusOK, existing)
+		return
+	}
+
+	if nar.OnlyReturnExisting {
+		writeProblem(w, http.StatusBadRequest, "accountDoesNotExist", "no account exists for this key")
+		return
+	}
+
+	account := &Account{
+		ID:                   newID(),
+		JWKThumb:             thumb,
+		JWK:                  jwk,
+		Contact:              nar.Contact,
+		Status:               StatusValid,
+		TermsOfServiceAgreed: nar.TermsOfServiceAgreed,
+	}
+	if err := s.Store.PutAccount(account); err != nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+
+	w.Header().Set("Location", s.url("/acme/account/"+account.ID))
+	writeJSON(w, http.StatusCreated, account)
+}
+
+// thumbprint computes the JWK thumbprint (RFC 7638) used as an account's
+// stable identity, since ACME accounts are keyed by their public key
+// rather than an assigned username.
+func thumbprint(jwk *jose.JSONWebKey) (string, error) {
+	thumb, err := jwk.Thumbprint(cryptoSHA256)
+	if err != nil {
+		return "", err
+	}
+	return encodeThumbprint(thumb), nil
+}
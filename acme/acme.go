@@ -0,0 +1,106 @@
+// Package acme implements an RFC 8555 (ACME v2) front-end over an
+// existing cfssl signer.Signer, so clients such as certbot or
+// cert-manager can obtain certificates from a cfssl-backed private CA
+// without speaking cfssl's own API.
+//
+// The package only implements the protocol surface; it does not run an
+// HTTP server itself. Embed a *Server's handlers into the host's mux (see
+// cmd/multirootca for cfssl's own CA server).
+package acme
+
+import (
+	"crypto/x509"
+	"time"
+
+	"github.com/cloudflare/cfssl/helpers"
+	"gopkg.in/square/go-jose.v2"
+)
+
+// Status is the lifecycle state of an Order, Authorization or Challenge,
+// per RFC 8555 §7.1.6.
+type Status string
+
+// Statuses shared across orders, authorizations and challenges.
+const (
+	StatusPending     Status = "pending"
+	StatusProcessing  Status = "processing"
+	StatusValid       Status = "valid"
+	StatusInvalid     Status = "invalid"
+	StatusReady       Status = "ready"
+	StatusDeactivated Status = "deactivated"
+	StatusExpired     Status = "expired"
+	StatusRevoked     Status = "revoked"
+)
+
+// Identifier is an ACME identifier, e.g. {"type": "dns", "value":
+// "example.com"}.
+type Identifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Account is an ACME account, keyed by the JWK used to sign its
+// requests.
+type Account struct {
+	ID                   string           `json:"-"`
+	JWKThumb             string           `json:"-"`
+	JWK                  *jose.JSONWebKey `json:"-"`
+	Contact              []string         `json:"contact,omitempty"`
+	Status               Status           `json:"status"`
+	TermsOfServiceAgreed bool             `json:"termsOfServiceAgreed,omitempty"`
+}
+
+// Order is an ACME order, tracking the certificate a client is in the
+// process of obtaining for a set of Identifiers.
+type Order struct {
+	ID             string       `json:"-"`
+	AccountID      string       `json:"-"`
+	Status         Status       `json:"status"`
+	Expires        time.Time    `json:"expires"`
+	Identifiers    []Identifier `json:"identifiers"`
+	Authorizations []string     `json:"authorizations"`
+	Finalize       string       `json:"finalize"`
+	Certificate    string       `json:"certificate,omitempty"`
+
+	// Label is the cfssl signer label and Profile the signing profile
+	// this order's certificate should be issued under, resolved from
+	// the server's label mapping when the order is created.
+	Label   string `json:"-"`
+	Profile string `json:"-"`
+
+	// CertDER holds the issued certificate, PEM-encoded as signer.Sign
+	// returns it, once finalize has run, so
+	// the /cert/{id} resource can serve it without re-deriving it from
+	// the signer's own store.
+	CertDER []byte `json:"-"`
+}
+
+// Authorization is an ACME authorization: proof of control over a single
+// Identifier, established by completing one of its Challenges.
+type Authorization struct {
+	ID         string       `json:"-"`
+	OrderID    string       `json:"-"`
+	Identifier Identifier   `json:"identifier"`
+	Status     Status       `json:"status"`
+	Expires    time.Time    `json:"expires"`
+	Challenges []*Challenge `json:"challenges"`
+}
+
+// Challenge is a single proof-of-control mechanism offered for an
+// Authorization.
+type Challenge struct {
+	ID        string `json:"-"`
+	AuthzID   string `json:"-"`
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	Token     string `json:"token"`
+	Status    Status `json:"status"`
+	Validated string `json:"validated,omitempty"`
+}
+
+// parseLeaf re-parses a freshly issued, PEM-encoded certificate (as
+// signer.Sign returns it) so Finalize can read back its NotAfter for the
+// issuance expiry metric.
+func parseLeaf(cert []byte) (*x509.Certificate, error) {
+	return helpers.ParseCertificatePEM(cert)
+}
@@ -0,0 +1,141 @@
+package acme
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/cfssl/log"
+)
+
+// Validator checks that a Challenge's proof of control is actually in
+// place for identifier. http-01 and dns-01 below are the two RFC 8555
+// validators this package ships; the validators map is unexported, so
+// other Challenge.Type values cannot be added from outside the package.
+type Validator func(identifier string, c *Challenge, keyAuthorization string) error
+
+// validators maps challenge type to the Validator that checks it.
+var validators = map[string]Validator{
+	"http-01": validateHTTP01,
+	"dns-01":  validateDNS01,
+}
+
+// httpClient is used to fetch http-01 challenge responses; a short
+// timeout keeps a slow or hung target from blocking order processing.
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// validateHTTP01 implements RFC 8555 §8.3: fetch
+// http://<domain>/.well-known/acme-challenge/<token> and compare the body
+// to the expected key authorization.
+func validateHTTP01(identifier string, c *Challenge, keyAuthorization string) error {
+	url := fmt.Sprintf("http://%s/.well-known/acme-challenge/%s", identifier, c.Token)
+
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("http-01: fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("http-01: %s returned status %d", url, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("http-01: reading response from %s: %w", url, err)
+	}
+
+	if strings.TrimSpace(string(body)) != keyAuthorization {
+		return fmt.Errorf("http-01: unexpected key authorization at %s", url)
+	}
+	return nil
+}
+
+// validateDNS01 implements RFC 8555 §8.4: look up
+// _acme-challenge.<domain> TXT and compare it to the base64url(SHA-256)
+// digest of the expected key authorization.
+func validateDNS01(identifier string, c *Challenge, keyAuthorization string) error {
+	name := "_acme-challenge." + identifier
+
+	txts, err := net.LookupTXT(name)
+	if err != nil {
+		return fmt.Errorf("dns-01: looking up TXT for %s: %w", name, err)
+	}
+
+	want := dns01Digest(keyAuthorization)
+	for _, txt := range txts {
+		if txt == want {
+			return nil
+		}
+	}
+	return fmt.Errorf("dns-01: no TXT record at %s matched the expected digest", name)
+}
+
+// RespondToChallenge serves POST /acme/challenge/{id}, triggering
+// validation. A real deployment validates asynchronously and polls; this
+// validates inline and updates the challenge/authorization status before
+// responding, which is simpler and adequate at cfssl's expected QPS for
+// this endpoint.
+func (s *Server) RespondToChallenge(w http.ResponseWriter, req *http.Request, id string) {
+	defer s.setNonce(w)
+
+	account, _, ok := s.authenticated(w, req)
+	if !ok {
+		return
+	}
+
+	c, err := s.Store.GetChallenge(id)
+	if err != nil {
+		writeProblem(w, http.StatusNotFound, "malformed", "no such challenge")
+		return
+	}
+
+	authz, err := s.Store.GetAuthorization(c.AuthzID)
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+
+	order, err := s.Store.GetOrder(authz.OrderID)
+	if err != nil || order.AccountID != account.ID {
+		writeProblem(w, http.StatusUnauthorized, "unauthorized", "challenge does not belong to this account")
+		return
+	}
+
+	keyAuth := c.Token + "." + account.JWKThumb
+
+	validate, ok := validators[c.Type]
+	if !ok {
+		writeProblem(w, http.StatusBadRequest, "malformed", "unsupported challenge type "+c.Type)
+		return
+	}
+
+	c.Status = StatusProcessing
+	s.Store.PutChallenge(c)
+
+	if err := validate(authz.Identifier.Value, c, keyAuth); err != nil {
+		log.Warningf("acme: challenge %s failed validation: %v", c.ID, err)
+		c.Status = StatusInvalid
+		authz.Status = StatusInvalid
+	} else {
+		c.Status = StatusValid
+		c.Validated = time.Now().Format(time.RFC3339)
+		authz.Status = StatusValid
+	}
+
+	s.Store.PutChallenge(c)
+	s.Store.PutAuthorization(authz)
+
+	// Promote the order to ready as soon as its last outstanding
+	// authorization turns valid, so Finalize can tell a client that
+	// skipped validation from one that actually completed it.
+	if authz.Status == StatusValid && order.Status == StatusPending && s.orderReady(order) {
+		order.Status = StatusReady
+		s.Store.PutOrder(order)
+	}
+
+	writeJSON(w, http.StatusOK, c)
+}
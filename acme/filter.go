@@ -0,0 +1,12 @@
+package acme
+
+import "github.com/cloudflare/cfssl/signer"
+
+// Filter mirrors the signature of cfssl's own CA server filters
+// (cmd/multirootca's policy pipeline): given the resolved label and the
+// SignRequest about to be signed, it returns false to reject the
+// request, and may rewrite fields of req (e.g. clamping NotAfter) before
+// returning true. Server.Filters lets an embedder share that same
+// pipeline with ACME-issued certificates, since Finalize would otherwise
+// bypass it by calling the signer directly.
+type Filter func(label string, req *signer.SignRequest) bool
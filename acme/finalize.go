@@ -0,0 +1,120 @@
+package acme
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cloudflare/cfssl/log"
+	"github.com/cloudflare/cfssl/signer"
+)
+
+type finalizeRequest struct {
+	CSR string `json:"csr"`
+}
+
+// Finalize serves POST /acme/order/{id}/finalize (RFC 8555 §7.4). It
+// constructs a signer.SignRequest from the ACME-supplied CSR and signs it
+// with the label/profile chosen for this order when it was created,
+// running it through s.Filters[order.Label] and reporting to s.Metrics
+// exactly as dispatchRequest does for cfssl API requests, so an embedder
+// that wires those up (see cmd/multirootca) gets the same policy
+// enforcement and observability for ACME-issued certificates.
+func (s *Server) Finalize(w http.ResponseWriter, req *http.Request, id string) {
+	defer s.setNonce(w)
+
+	account, payload, ok := s.authenticated(w, req)
+	if !ok {
+		return
+	}
+
+	order, err := s.Store.GetOrder(id)
+	if err != nil {
+		writeProblem(w, http.StatusNotFound, "malformed", "no such order")
+		return
+	}
+	if order.AccountID != account.ID {
+		writeProblem(w, http.StatusUnauthorized, "unauthorized", "order does not belong to this account")
+		return
+	}
+	if order.Status != StatusReady {
+		writeProblem(w, http.StatusForbidden, "orderNotReady", "order is not ready to be finalized")
+		return
+	}
+
+	var fr finalizeRequest
+	if err := unmarshalJSON(payload, &fr); err != nil || fr.CSR == "" {
+		writeProblem(w, http.StatusBadRequest, "malformed", "finalize request is missing a csr")
+		return
+	}
+
+	sgnr, ok := s.Signers[order.Label]
+	if !ok {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", "no signer configured for label "+order.Label)
+		return
+	}
+
+	hosts := make([]string, len(order.Identifiers))
+	for i, id := range order.Identifiers {
+		hosts[i] = id.Value
+	}
+
+	signReq := signer.SignRequest{
+		Hosts:   hosts,
+		Request: der2CSRPEM(fr.CSR),
+		Profile: order.Profile,
+		Label:   order.Label,
+	}
+
+	for _, f := range s.Filters[order.Label] {
+		if !f(order.Label, &signReq) {
+			s.incError("policy")
+			log.Warningf("acme: finalize for order %s rejected by policy", order.ID)
+			order.Status = StatusInvalid
+			s.Store.PutOrder(order)
+			writeProblem(w, http.StatusForbidden, "rejectedIdentifier", "request rejected by policy")
+			return
+		}
+	}
+
+	signStart := time.Now()
+	cert, err := sgnr.Sign(signReq)
+	s.observeSignLatency(order.Label, order.Profile, time.Since(signStart).Seconds())
+	if err != nil {
+		s.incError("signing")
+		log.Warningf("acme: finalize failed for order %s: %v", order.ID, err)
+		order.Status = StatusInvalid
+		s.Store.PutOrder(order)
+		writeProblem(w, http.StatusBadRequest, "badCSR", err.Error())
+		return
+	}
+
+	x509Cert, err := parseLeaf(cert)
+	if err != nil {
+		s.incError("parse")
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", "issued certificate failed to parse: "+err.Error())
+		return
+	}
+	s.observeCertExpiry(order.Label, time.Until(x509Cert.NotAfter).Hours()/24)
+
+	order.CertDER = cert
+	order.Status = StatusValid
+	order.Certificate = s.url("/acme/cert/" + order.ID)
+	if err := s.Store.PutOrder(order); err != nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, order)
+}
+
+// GetCertificate serves GET /acme/cert/{id}.
+func (s *Server) GetCertificate(w http.ResponseWriter, req *http.Request, id string) {
+	order, err := s.Store.GetOrder(id)
+	if err != nil || order.Status != StatusValid || len(order.CertDER) == 0 {
+		writeProblem(w, http.StatusNotFound, "malformed", "no certificate for this order")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pem-certificate-chain")
+	w.Write(order.CertDER)
+}
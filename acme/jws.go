@@ -0,0 +1,74 @@
+package acme
+
+import (
+	"encoding/json"
+	"errors"
+
+	"gopkg.in/square/go-jose.v2"
+)
+
+// protectedHeader is the subset of a JWS's protected header ACME cares
+// about: either a full JWK (new-account, or any request signed by a key
+// that already has an account) or a key ID referencing a previously
+// registered account.
+type protectedHeader struct {
+	Alg   string          `json:"alg"`
+	JWK   json.RawMessage `json:"jwk,omitempty"`
+	Kid   string          `json:"kid,omitempty"`
+	Nonce string          `json:"nonce"`
+	URL   string          `json:"url"`
+}
+
+// verifyJWS parses a JWS-serialized ACME request body and, if its
+// protected header embeds a jwk (as new-account and the first new-order
+// of a key rollover do), verifies it and returns the payload.
+//
+// When the header instead carries a kid (every other authenticated
+// request), verification is deferred: the caller must resolve kid to a
+// previously registered account's key and call verifyJWSWithKey itself.
+// In that case payload is nil and jwk is nil; only header is populated.
+//
+// The caller is responsible for checking the nonce and URL claims in the
+// protected header against the request it actually received.
+func verifyJWS(body []byte) (payload []byte, header protectedHeader, jwk *jose.JSONWebKey, err error) {
+	sig, err := jose.ParseSigned(string(body))
+	if err != nil {
+		return nil, header, nil, err
+	}
+	if len(sig.Signatures) != 1 {
+		return nil, header, nil, errors.New("acme: exactly one JWS signature is required")
+	}
+
+	if err := json.Unmarshal(sig.Signatures[0].Protected.Bytes(), &header); err != nil {
+		return nil, header, nil, err
+	}
+
+	switch {
+	case len(header.JWK) > 0:
+		jwk = &jose.JSONWebKey{}
+		if err := jwk.UnmarshalJSON(header.JWK); err != nil {
+			return nil, header, nil, err
+		}
+		payload, err = sig.Verify(jwk)
+		if err != nil {
+			return nil, header, nil, err
+		}
+	case header.Kid != "":
+		// Verified by the caller via verifyJWSWithKey once it has
+		// resolved kid to the signing account's key.
+	default:
+		return nil, header, nil, errors.New("acme: JWS protected header has neither jwk nor kid")
+	}
+
+	return payload, header, jwk, nil
+}
+
+// verifyJWSWithKey verifies body against a known key, for the case where
+// the protected header carries a kid rather than an embedded jwk.
+func verifyJWSWithKey(body []byte, key *jose.JSONWebKey) (payload []byte, err error) {
+	sig, err := jose.ParseSigned(string(body))
+	if err != nil {
+		return nil, err
+	}
+	return sig.Verify(key)
+}
@@ -0,0 +1,17 @@
+package acme
+
+// MetricsHooks lets an embedder (cmd/multirootca) observe ACME issuance
+// with the same metrics it records for its own /sign API, without this
+// package importing prometheus or depending on cmd/multirootca itself.
+// Every field is optional; Server only calls the ones that are set.
+type MetricsHooks struct {
+	// ObserveSignLatency is called with label, profile and the number
+	// of seconds the underlying signer.Sign call took.
+	ObserveSignLatency func(label, profile string, seconds float64)
+	// IncError is called once per finalize request that fails, tagged
+	// with the stage it failed at ("policy", "signing", "parse").
+	IncError func(class string)
+	// ObserveCertExpiry is called with label and days-until-expiry for
+	// every certificate successfully issued.
+	ObserveCertExpiry func(label string, daysUntilExpiry float64)
+}
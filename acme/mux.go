@@ -0,0 +1,48 @@
+package acme
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudflare/cfssl/ocsp"
+)
+
+// Handler returns an http.Handler serving every ACME resource this
+// package implements, rooted at "/acme/". statusStore may be nil, in
+// which case /acme/revoke-cert always fails; pass the same store the CA
+// server's OCSP responder uses so revocations reach it (see RevokeCert for
+// responses the responder has already pre-signed).
+func (s *Server) Handler(statusStore ocsp.StatusStore) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/acme/directory", s.Directory)
+	mux.HandleFunc("/acme/new-nonce", s.NewNonce)
+	mux.HandleFunc("/acme/new-account", s.NewAccount)
+	mux.HandleFunc("/acme/new-order", s.NewOrder)
+	mux.HandleFunc("/acme/revoke-cert", func(w http.ResponseWriter, req *http.Request) {
+		s.RevokeCert(w, req, statusStore)
+	})
+
+	mux.HandleFunc("/acme/order/", func(w http.ResponseWriter, req *http.Request) {
+		rest := strings.TrimPrefix(req.URL.Path, "/acme/order/")
+		if strings.HasSuffix(rest, "/finalize") {
+			s.Finalize(w, req, strings.TrimSuffix(rest, "/finalize"))
+			return
+		}
+		s.GetOrder(w, req, rest)
+	})
+
+	mux.HandleFunc("/acme/authz/", func(w http.ResponseWriter, req *http.Request) {
+		s.GetAuthorization(w, req, strings.TrimPrefix(req.URL.Path, "/acme/authz/"))
+	})
+
+	mux.HandleFunc("/acme/challenge/", func(w http.ResponseWriter, req *http.Request) {
+		s.RespondToChallenge(w, req, strings.TrimPrefix(req.URL.Path, "/acme/challenge/"))
+	})
+
+	mux.HandleFunc("/acme/cert/", func(w http.ResponseWriter, req *http.Request) {
+		s.GetCertificate(w, req, strings.TrimPrefix(req.URL.Path, "/acme/cert/"))
+	})
+
+	return mux
+}
@@ -0,0 +1,49 @@
+package acme
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+)
+
+// noncePool hands out and redeems single-use nonces, per RFC 8555 §7.2.
+// Nonces are only ever checked against this process's memory: a cfssl CA
+// server run behind a load balancer needs sticky sessions or a shared
+// nonce pool (e.g. backed by the same Store as orders) for this to work
+// across replicas.
+type noncePool struct {
+	mu     sync.Mutex
+	active map[string]bool
+}
+
+func newNoncePool() *noncePool {
+	return &noncePool{active: map[string]bool{}}
+}
+
+// New mints and records a fresh nonce.
+func (p *noncePool) New() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(buf)
+
+	p.mu.Lock()
+	p.active[nonce] = true
+	p.mu.Unlock()
+
+	return nonce, nil
+}
+
+// Redeem consumes nonce, returning false (and not consuming it) if it was
+// never issued or has already been used.
+func (p *noncePool) Redeem(nonce string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active[nonce] {
+		return false
+	}
+	delete(p.active, nonce)
+	return true
+}
@@ -0,0 +1,175 @@
+package acme
+
+import (
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+type newOrderRequest struct {
+	Identifiers []Identifier `json:"identifiers"`
+}
+
+// NewOrder serves POST /acme/new-order (RFC 8555 §7.4).
+func (s *Server) NewOrder(w http.ResponseWriter, req *http.Request) {
+	defer s.setNonce(w)
+
+	account, payload, ok := s.authenticated(w, req)
+	if !ok {
+		return
+	}
+
+	var nor newOrderRequest
+	if err := unmarshalJSON(payload, &nor); err != nil || len(nor.Identifiers) == 0 {
+		writeProblem(w, http.StatusBadRequest, "malformed", "order must name at least one identifier")
+		return
+	}
+
+	label, profile := s.labelFor(nor.Identifiers)
+
+	order := &Order{
+		ID:          newID(),
+		AccountID:   account.ID,
+		Status:      StatusPending,
+		Expires:     time.Now().Add(expiresIn),
+		Identifiers: nor.Identifiers,
+		Label:       label,
+		Profile:     profile,
+	}
+	order.Finalize = s.url("/acme/order/" + order.ID + "/finalize")
+
+	for _, id := range nor.Identifiers {
+		authz := &Authorization{
+			ID:         newID(),
+			OrderID:    order.ID,
+			Identifier: id,
+			Status:     StatusPending,
+			Expires:    order.Expires,
+		}
+		authz.Challenges = []*Challenge{
+			{ID: newID(), AuthzID: authz.ID, Type: "http-01", Token: newID(), Status: StatusPending},
+			{ID: newID(), AuthzID: authz.ID, Type: "dns-01", Token: newID(), Status: StatusPending},
+		}
+		for _, c := range authz.Challenges {
+			c.URL = s.url("/acme/challenge/" + c.ID)
+			if err := s.Store.PutChallenge(c); err != nil {
+				writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+				return
+			}
+		}
+		if err := s.Store.PutAuthorization(authz); err != nil {
+			writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+			return
+		}
+		order.Authorizations = append(order.Authorizations, s.url("/acme/authz/"+authz.ID))
+	}
+
+	if err := s.Store.PutOrder(order); err != nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+
+	w.Header().Set("Location", s.url("/acme/order/"+order.ID))
+	writeJSON(w, http.StatusCreated, order)
+}
+
+// GetOrder serves GET /acme/order/{id}.
+func (s *Server) GetOrder(w http.ResponseWriter, req *http.Request, id string) {
+	order, err := s.Store.GetOrder(id)
+	if err != nil {
+		writeProblem(w, http.StatusNotFound, "malformed", "no such order")
+		return
+	}
+	writeJSON(w, http.StatusOK, order)
+}
+
+// GetAuthorization serves GET /acme/authz/{id}.
+func (s *Server) GetAuthorization(w http.ResponseWriter, req *http.Request, id string) {
+	authz, err := s.Store.GetAuthorization(id)
+	if err != nil {
+		writeProblem(w, http.StatusNotFound, "malformed", "no such authorization")
+		return
+	}
+	writeJSON(w, http.StatusOK, authz)
+}
+
+// authenticated verifies the JWS on req's body and resolves it to the
+// Account that signed it, handling both the embedded-jwk (new-account)
+// and kid (every later request) forms.
+func (s *Server) authenticated(w http.ResponseWriter, req *http.Request) (*Account, []byte, bool) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", err.Error())
+		return nil, nil, false
+	}
+
+	payload, header, jwk, err := verifyJWS(body)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", "invalid JWS: "+err.Error())
+		return nil, nil, false
+	}
+
+	if !s.nonces.Redeem(header.Nonce) {
+		writeProblem(w, http.StatusBadRequest, "badNonce", "nonce is missing, unknown, or already used")
+		return nil, nil, false
+	}
+	if header.URL != s.url(req.URL.Path) {
+		writeProblem(w, http.StatusBadRequest, "unauthorized", "JWS url claim does not match the request")
+		return nil, nil, false
+	}
+
+	if jwk != nil {
+		thumb, err := thumbprint(jwk)
+		if err != nil {
+			writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+			return nil, nil, false
+		}
+		account, err := s.Store.GetAccountByJWKThumbprint(thumb)
+		if err != nil {
+			writeProblem(w, http.StatusBadRequest, "accountDoesNotExist", "no account for this key")
+			return nil, nil, false
+		}
+		return account, payload, true
+	}
+
+	// header.Kid identifies the account; look it up and verify the JWS
+	// against its registered key, since verifyJWS only checks the
+	// signature for the embedded-jwk case.
+	accountID := lastPathSegment(header.Kid)
+	account, err := s.Store.GetAccount(accountID)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "accountDoesNotExist", "no such account")
+		return nil, nil, false
+	}
+
+	payload, err = verifyJWSWithKey(body, account.JWK)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", "invalid JWS: "+err.Error())
+		return nil, nil, false
+	}
+
+	return account, payload, true
+}
+
+// orderReady reports whether every authorization on order has reached
+// StatusValid. Per RFC 8555 §7.1.6 an order only transitions from
+// pending to ready once all of its authorizations are valid; Finalize
+// must refuse to issue until this holds.
+func (s *Server) orderReady(order *Order) bool {
+	for _, url := range order.Authorizations {
+		authz, err := s.Store.GetAuthorization(lastPathSegment(url))
+		if err != nil || authz.Status != StatusValid {
+			return false
+		}
+	}
+	return true
+}
+
+func lastPathSegment(url string) string {
+	for i := len(url) - 1; i >= 0; i-- {
+		if url[i] == '/' {
+			return url[i+1:]
+		}
+	}
+	return url
+}
@@ -0,0 +1,59 @@
+package acme
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/cloudflare/cfssl/ocsp"
+)
+
+type revokeCertRequest struct {
+	Certificate string `json:"certificate"`
+	Reason      int    `json:"reason"`
+}
+
+// RevokeCert serves POST /acme/revoke-cert (RFC 8555 §7.6), flipping the
+// certificate's status in the same ocsp.StatusStore the CA server
+// publishes to on issuance, so OCSP responses signed on demand reflect
+// the revocation immediately. A response an ocsp.Responder has already
+// pre-signed for the serial is not invalidated here and keeps being
+// served until that cache is refreshed.
+func (s *Server) RevokeCert(w http.ResponseWriter, req *http.Request, store ocsp.StatusStore) {
+	defer s.setNonce(w)
+
+	_, payload, ok := s.authenticated(w, req)
+	if !ok {
+		return
+	}
+
+	var rr revokeCertRequest
+	if err := unmarshalJSON(payload, &rr); err != nil || rr.Certificate == "" {
+		writeProblem(w, http.StatusBadRequest, "malformed", "revoke-cert request is missing a certificate")
+		return
+	}
+
+	der, err := base64.RawURLEncoding.DecodeString(rr.Certificate)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", "certificate is not valid base64url")
+		return
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", "certificate does not parse: "+err.Error())
+		return
+	}
+
+	if store == nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", "this server has no status store configured for revocation")
+		return
+	}
+
+	if err := store.Revoke(cert.SerialNumber.String(), rr.Reason); err != nil {
+		writeProblem(w, http.StatusBadRequest, "malformed", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
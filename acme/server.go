@@ -0,0 +1,159 @@
+package acme
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/cfssl/log"
+	"github.com/cloudflare/cfssl/signer"
+)
+
+// Directory is the RFC 8555 §7.1.1 directory resource, telling clients
+// where each ACME resource lives.
+type Directory struct {
+	NewNonce   string `json:"newNonce"`
+	NewAccount string `json:"newAccount"`
+	NewOrder   string `json:"newOrder"`
+	RevokeCert string `json:"revokeCert"`
+}
+
+// LabelMapper resolves the cfssl signer label and profile a newly created
+// order should be issued under, given the identifiers the client
+// requested. While Server.ResolveLabel is nil, every order gets the
+// server's DefaultLabel/DefaultProfile; callers with more than one
+// signing label should set it.
+type LabelMapper func(identifiers []Identifier) (label, profile string)
+
+// Server implements the ACME v2 resources, delegating final issuance to
+// signers - the same label-keyed signer.Signer map dispatchRequest uses
+// in cfssl's CA server.
+type Server struct {
+	BaseURL        string
+	Signers        map[string]signer.Signer
+	DefaultLabel   string
+	DefaultProfile string
+	Store          Store
+	ResolveLabel   LabelMapper
+
+	// Filters, keyed by label, is run against every finalize request
+	// before it reaches the signer, exactly like dispatchRequest's own
+	// policy pipeline. Nil (the default) runs no filters.
+	Filters map[string][]Filter
+	// Metrics, if set, is notified of ACME issuance the same way
+	// dispatchRequest notifies its own Prometheus collectors.
+	Metrics *MetricsHooks
+
+	nonces *noncePool
+}
+
+// NewServer builds a Server delegating issuance to signers.
+func NewServer(baseURL string, signers map[string]signer.Signer, defaultLabel string, store Store) *Server {
+	return &Server{
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		Signers:      signers,
+		DefaultLabel: defaultLabel,
+		Store:        store,
+		nonces:       newNoncePool(),
+	}
+}
+
+// url builds an absolute URL for one of this server's resources.
+func (s *Server) url(path string) string {
+	return s.BaseURL + path
+}
+
+// Directory serves GET /acme/directory.
+func (s *Server) Directory(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, Directory{
+		NewNonce:   s.url("/acme/new-nonce"),
+		NewAccount: s.url("/acme/new-account"),
+		NewOrder:   s.url("/acme/new-order"),
+		RevokeCert: s.url("/acme/revoke-cert"),
+	})
+}
+
+// NewNonce serves HEAD/GET /acme/new-nonce.
+func (s *Server) NewNonce(w http.ResponseWriter, req *http.Request) {
+	nonce, err := s.nonces.New()
+	if err != nil {
+		writeProblem(w, http.StatusInternalServerError, "serverInternal", err.Error())
+		return
+	}
+	w.Header().Set("Replay-Nonce", nonce)
+	w.WriteHeader(http.StatusOK)
+}
+
+// setNonce attaches a fresh Replay-Nonce header to every ACME response,
+// per RFC 8555 §6.5.
+func (s *Server) setNonce(w http.ResponseWriter) {
+	if nonce, err := s.nonces.New(); err == nil {
+		w.Header().Set("Replay-Nonce", nonce)
+	}
+}
+
+// labelFor resolves the signer label/profile for identifiers, using
+// s.ResolveLabel if set.
+func (s *Server) labelFor(identifiers []Identifier) (label, profile string) {
+	if s.ResolveLabel != nil {
+		return s.ResolveLabel(identifiers)
+	}
+	return s.DefaultLabel, s.DefaultProfile
+}
+
+// incError reports class to s.Metrics.IncError, if metrics are wired up.
+func (s *Server) incError(class string) {
+	if s.Metrics != nil && s.Metrics.IncError != nil {
+		s.Metrics.IncError(class)
+	}
+}
+
+// observeSignLatency reports seconds to s.Metrics.ObserveSignLatency, if
+// metrics are wired up.
+func (s *Server) observeSignLatency(label, profile string, seconds float64) {
+	if s.Metrics != nil && s.Metrics.ObserveSignLatency != nil {
+		s.Metrics.ObserveSignLatency(label, profile, seconds)
+	}
+}
+
+// observeCertExpiry reports daysUntilExpiry to s.Metrics.ObserveCertExpiry,
+// if metrics are wired up.
+func (s *Server) observeCertExpiry(label string, daysUntilExpiry float64) {
+	if s.Metrics != nil && s.Metrics.ObserveCertExpiry != nil {
+		s.Metrics.ObserveCertExpiry(label, daysUntilExpiry)
+	}
+}
+
+func newID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("acme: error writing response: %v", err)
+	}
+}
+
+// problem is an RFC 7807 problem document, as RFC 8555 §6.7 requires for
+// ACME errors.
+type problem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail"`
+}
+
+func writeProblem(w http.ResponseWriter, status int, acmeType, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(problem{Type: "urn:ietf:params:acme:error:" + acmeType, Detail: detail})
+}
+
+// expiresIn is how long a freshly created order or authorization is
+// valid for before the client must start over.
+const expiresIn = 7 * 24 * time.Hour
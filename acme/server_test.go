@@ -0,0 +1,61 @@
+package acme
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cloudflare/cfssl/config"
+	"github.com/cloudflare/cfssl/signer"
+)
+
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(req signer.SignRequest) ([]byte, error) {
+	return []byte("-----BEGIN CERTIFICATE-----\nfake\n-----END CERTIFICATE-----\n"), nil
+}
+func (fakeSigner) SigAlgo() x509.SignatureAlgorithm { return x509.SHA256WithRSA }
+func (fakeSigner) Policy() *config.Signing          { return &config.Signing{} }
+func (fakeSigner) SetPolicy(*config.Signing)        {}
+
+func newTestServer() *Server {
+	return NewServer("https://ca.example.com", map[string]signer.Signer{
+		"default": fakeSigner{},
+	}, "default", NewMemoryStore())
+}
+
+func TestDirectory(t *testing.T) {
+	s := newTestServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/acme/directory", nil)
+	w := httptest.NewRecorder()
+	s.Directory(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if want := "https://ca.example.com/acme/new-order"; !strings.Contains(w.Body.String(), want) {
+		t.Errorf("expected directory body to contain %q, got %s", want, w.Body.String())
+	}
+}
+
+func TestNewNonceSetsHeader(t *testing.T) {
+	s := newTestServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/acme/new-nonce", nil)
+	w := httptest.NewRecorder()
+	s.NewNonce(w, req)
+
+	nonce := w.Header().Get("Replay-Nonce")
+	if nonce == "" {
+		t.Fatal("expected a Replay-Nonce header")
+	}
+	if !s.nonces.Redeem(nonce) {
+		t.Error("expected the issued nonce to be redeemable exactly once")
+	}
+	if s.nonces.Redeem(nonce) {
+		t.Error("expected a second redemption of the same nonce to fail")
+	}
+}
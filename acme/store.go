@@ -0,0 +1,118 @@
+package acme
+
+import "errors"
+
+// ErrNotFound is returned by a Store when the requested object does not
+// exist.
+var ErrNotFound = errors.New("acme: not found")
+
+// Store persists ACME accounts, orders, authorizations and challenges.
+// It plays the same role for the ACME front-end that ocsp.StatusStore
+// plays for the OCSP responder: a small, swappable persistence seam so
+// the protocol logic doesn't care whether records live in BoltDB, SQL, or
+// memory.
+type Store interface {
+	PutAccount(a *Account) error
+	GetAccount(id string) (*Account, error)
+	GetAccountByJWKThumbprint(thumb string) (*Account, error)
+
+	PutOrder(o *Order) error
+	GetOrder(id string) (*Order, error)
+
+	PutAuthorization(a *Authorization) error
+	GetAuthorization(id string) (*Authorization, error)
+
+	PutChallenge(c *Challenge) error
+	GetChallenge(id string) (*Challenge, error)
+}
+
+// MemoryStore is an in-process Store, suitable for development and for
+// single-node deployments that don't need orders to survive a restart.
+type MemoryStore struct {
+	accounts       map[string]*Account
+	accountsByJWK  map[string]*Account
+	orders         map[string]*Order
+	authorizations map[string]*Authorization
+	challenges     map[string]*Challenge
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		accounts:       map[string]*Account{},
+		accountsByJWK:  map[string]*Account{},
+		orders:         map[string]*Order{},
+		authorizations: map[string]*Authorization{},
+		challenges:     map[string]*Challenge{},
+	}
+}
+
+// PutAccount implements Store.
+func (s *MemoryStore) PutAccount(a *Account) error {
+	s.accounts[a.ID] = a
+	s.accountsByJWK[a.JWKThumb] = a
+	return nil
+}
+
+// GetAccount implements Store.
+func (s *MemoryStore) GetAccount(id string) (*Account, error) {
+	a, ok := s.accounts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return a, nil
+}
+
+// GetAccountByJWKThumbprint implements Store.
+func (s *MemoryStore) GetAccountByJWKThumbprint(thumb string) (*Account, error) {
+	a, ok := s.accountsByJWK[thumb]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return a, nil
+}
+
+// PutOrder implements Store.
+func (s *MemoryStore) PutOrder(o *Order) error {
+	s.orders[o.ID] = o
+	return nil
+}
+
+// GetOrder implements Store.
+func (s *MemoryStore) GetOrder(id string) (*Order, error) {
+	o, ok := s.orders[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return o, nil
+}
+
+// PutAuthorization implements Store.
+func (s *MemoryStore) PutAuthorization(a *Authorization) error {
+	s.authorizations[a.ID] = a
+	return nil
+}
+
+// GetAuthorization implements Store.
+func (s *MemoryStore) GetAuthorization(id string) (*Authorization, error) {
+	a, ok := s.authorizations[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return a, nil
+}
+
+// PutChallenge implements Store.
+func (s *MemoryStore) PutChallenge(c *Challenge) error {
+	s.challenges[c.ID] = c
+	return nil
+}
+
+// GetChallenge implements Store.
+func (s *MemoryStore) GetChallenge(id string) (*Challenge, error) {
+	c, ok := s.challenges[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return c, nil
+}
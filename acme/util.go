@@ -0,0 +1,39 @@
+package acme
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+)
+
+// cryptoSHA256 is the hash jose.JSONWebKey.Thumbprint uses to compute an
+// RFC 7638 JWK thumbprint.
+const cryptoSHA256 = crypto.SHA256
+
+func unmarshalJSON(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func encodeThumbprint(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// dns01Digest computes the value a dns-01 TXT record must carry for a
+// given key authorization, per RFC 8555 §8.4.
+func dns01Digest(keyAuthorization string) string {
+	sum := sha256.Sum256([]byte(keyAuthorization))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// der2CSRPEM re-encodes the base64url-encoded DER CSR an ACME finalize
+// request carries (RFC 8555 §7.4) as the PEM cfssl's signer.SignRequest
+// expects.
+func der2CSRPEM(b64URLDER string) string {
+	der, err := base64.RawURLEncoding.DecodeString(b64URLDER)
+	if err != nil {
+		return ""
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/cloudflare/cfssl/acme"
+)
+
+// acmeServer serves the ACME v2 endpoints when configured (see
+// initACME). It is nil, and /acme/* is not mounted, unless the CA server
+// config enables it.
+var acmeServer *acme.Server
+
+// initACME wires up an acme.Server delegating issuance to signers, so
+// clients such as certbot or cert-manager can request certificates
+// through the same labels and filter pipeline dispatchRequest uses. The
+// server's Filters and Metrics are wired from the same `filters` map and
+// Prometheus collectors dispatchRequest itself uses, so ACME-issued
+// certificates get identical policy enforcement and observability.
+func initACME(baseURL, defaultLabel string) {
+	acmeServer = acme.NewServer(baseURL, signers, defaultLabel, acme.NewMemoryStore())
+
+	acmeFilters := make(map[string][]acme.Filter, len(filters))
+	for label, fs := range filters {
+		for _, f := range fs {
+			acmeFilters[label] = append(acmeFilters[label], acme.Filter(f))
+		}
+	}
+	acmeServer.Filters = acmeFilters
+
+	acmeServer.Metrics = &acme.MetricsHooks{
+		ObserveSignLatency: func(label, profile string, seconds float64) {
+			promSignLatency.WithLabelValues(label, profile).Observe(seconds)
+		},
+		IncError: func(class string) {
+			promErrorsTotal.WithLabelValues(class).Inc()
+		},
+		ObserveCertExpiry: func(label string, daysUntilExpiry float64) {
+			promCertExpiryBucket.WithLabelValues(label, expiryBucket(daysUntilExpiry)).Inc()
+		},
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
+	"time"
 
 	"github.com/cloudflare/cfssl/api"
 	"github.com/cloudflare/cfssl/auth"
@@ -91,6 +92,9 @@ func fail(w http.ResponseWriter, req *http.Request, status, code int, msg, ad st
 func dispatchRequest(w http.ResponseWriter, req *http.Request) {
 	incRequests()
 
+	req, span := startSpan(req, "dispatchRequest")
+	defer span.End()
+
 	if req.Method != "POST" {
 		fail(w, req, http.StatusMethodNotAllowed, 1, "only POST is permitted", "")
 		return
@@ -134,6 +138,7 @@ func dispatchRequest(w http.ResponseWriter, req *http.Request) {
 	// should have been checked in NewAuthSignHandler.
 	policy := s.Policy()
 	if policy == nil {
+		promErrorsTotal.WithLabelValues(errClassPolicy).Inc()
 		fail(w, req, http.StatusInternalServerError, 1, "invalid policy", "signer was initialised without a signing policy")
 		return
 	}
@@ -144,36 +149,64 @@ func dispatchRequest(w http.ResponseWriter, req *http.Request) {
 	}
 
 	if profile == nil {
+		promErrorsTotal.WithLabelValues(errClassPolicy).Inc()
 		fail(w, req, http.StatusInternalServerError, 1, "invalid profile", "signer was initialised without any valid profiles")
 		return
 	}
 
 	if profile.Provider == nil {
+		promErrorsTotal.WithLabelValues(errClassAuth).Inc()
 		fail(w, req, http.StatusUnauthorized, 1, "authorisation required", "received unauthenticated request")
 		return
 	}
 
-	if !profile.Provider.Verify(&authReq) {
+	_, verifySpan := startSpan(req, "auth.Verify")
+	ok = profile.Provider.Verify(&authReq)
+	verifySpan.End()
+	if !ok {
+		promErrorsTotal.WithLabelValues(errClassAuth).Inc()
 		fail(w, req, http.StatusBadRequest, 1, "invalid token", "received authenticated request with invalid token")
 		return
 	}
 
 	if sigRequest.Request == "" {
+		promErrorsTotal.WithLabelValues(errClassParse).Inc()
 		fail(w, req, http.StatusBadRequest, 1, "invalid request", "empty request")
 		return
 	}
 
+	for _, f := range filters[sigRequest.Label] {
+		if !f(sigRequest.Label, &sigRequest) {
+			promErrorsTotal.WithLabelValues(errClassPolicy).Inc()
+			fail(w, req, http.StatusBadRequest, 1, "request rejected by policy", "")
+			return
+		}
+	}
+
+	signStart := time.Now()
+	_, signSpan := startSpan(req, "signer.Sign")
 	cert, err := s.Sign(sigRequest)
+	signSpan.End()
+	promSignLatency.WithLabelValues(sigRequest.Label, sigRequest.Profile).Observe(time.Since(signStart).Seconds())
 	if err != nil {
+		promErrorsTotal.WithLabelValues(errClassSign).Inc()
 		fail(w, req, http.StatusBadRequest, 1, "bad request", "signature failed: "+err.Error())
 		return
 	}
 
+	_, parseSpan := startSpan(req, "helpers.ParseCertificatePEM")
 	x509Cert, err := helpers.ParseCertificatePEM(cert)
+	parseSpan.End()
 	if err != nil {
+		promErrorsTotal.WithLabelValues(errClassParse).Inc()
 		fail(w, req, http.StatusInternalServerError, 1, "bad certificate", err.Error())
+		return
 	}
 
+	promCertExpiryBucket.WithLabelValues(sigRequest.Label, expiryBucket(time.Until(x509Cert.NotAfter).Hours()/24)).Inc()
+
+	publishStatus(sigRequest.Label, cert)
+
 	log.Infof("signature: requester=%s, label=%s, profile=%s, serialno=%s",
 		req.RemoteAddr, sigRequest.Label, sigRequest.Profile, x509Cert.SerialNumber)
 
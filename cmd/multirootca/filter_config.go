@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+)
+
+// filterConfig is the per-label policy pipeline configuration loaded from
+// the CA server's config file, e.g.:
+//
+//	{
+//	  "default": {
+//	    "san_allowlist": {"literal": ["example.com"], "regex": ["^.*\\.example\\.com$"]},
+//	    "enforce_key_strength": true,
+//	    "max_validity": "8760h",
+//	    "rate_limit": {"limit": 10, "window": "1h"}
+//	  }
+//	}
+type filterConfig struct {
+	SANAllowlist       *sanAllowlistConfig `json:"san_allowlist,omitempty"`
+	EnforceKeyStrength bool                `json:"enforce_key_strength,omitempty"`
+	MaxValidity        string              `json:"max_validity,omitempty"`
+	RateLimit          *rateLimitConfig    `json:"rate_limit,omitempty"`
+}
+
+type rateLimitConfig struct {
+	Limit  int    `json:"limit"`
+	Window string `json:"window"`
+}
+
+// loadFilters reads a JSON file mapping label to filterConfig and calls
+// RegisterFilter for every filter it describes.
+func loadFilters(path string) error {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var cfg map[string]filterConfig
+	if err := json.Unmarshal(buf, &cfg); err != nil {
+		return err
+	}
+
+	for label, lc := range cfg {
+		if lc.SANAllowlist != nil {
+			f, err := NewSANAllowlistFilter(*lc.SANAllowlist)
+			if err != nil {
+				return err
+			}
+			RegisterFilter(label, f)
+		}
+
+		if lc.EnforceKeyStrength {
+			RegisterFilter(label, NewKeyStrengthFilter())
+		}
+
+		if lc.MaxValidity != "" {
+			d, err := time.ParseDuration(lc.MaxValidity)
+			if err != nil {
+				return err
+			}
+			RegisterFilter(label, NewMaxValidityFilter(d))
+		}
+
+		if lc.RateLimit != nil {
+			window, err := time.ParseDuration(lc.RateLimit.Window)
+			if err != nil {
+				return err
+			}
+			RegisterFilter(label, NewCommonNameRateLimitFilter(lc.RateLimit.Limit, window))
+		}
+	}
+
+	return nil
+}
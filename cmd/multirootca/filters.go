@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/cloudflare/cfssl/log"
+	"github.com/cloudflare/cfssl/signer"
+)
+
+// RegisterFilter adds f to the policy pipeline run against every signing
+// request for label, in the order registered. A filter returning false
+// aborts the request before s.Sign is ever called.
+func RegisterFilter(label string, f filter) {
+	filters[label] = append(filters[label], f)
+}
+
+// sanAllowlistConfig is the JSON shape of a per-label SAN allowlist, as
+// loaded from the CA server's config file.
+type sanAllowlistConfig struct {
+	Literal []string `json:"literal"`
+	Regex   []string `json:"regex"`
+}
+
+// NewSANAllowlistFilter builds a filter rejecting any request whose hosts
+// are not all covered by either a literal match or a regular expression
+// from cfg. Besides req.Hosts, it also checks the names actually carried
+// by the CSR (DNSNames, IPAddresses and the Subject CN): the signer can
+// emit those names into the issued certificate regardless of what Hosts
+// says, so an allowlist that only looked at Hosts would be bypassable by
+// any client that can submit its own CSR.
+func NewSANAllowlistFilter(cfg sanAllowlistConfig) (filter, error) {
+	literal := map[string]bool{}
+	for _, h := range cfg.Literal {
+		literal[h] = true
+	}
+
+	patterns := make([]*regexp.Regexp, len(cfg.Regex))
+	for i, p := range cfg.Regex {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		patterns[i] = re
+	}
+
+	allowed := func(host string) bool {
+		if literal[host] {
+			return true
+		}
+		for _, re := range patterns {
+			if re.MatchString(host) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(label string, req *signer.SignRequest) bool {
+		hosts := req.Hosts
+
+		if block, _ := pem.Decode([]byte(req.Request)); block != nil {
+			if csr, err := x509.ParseCertificateRequest(block.Bytes); err == nil {
+				hosts = append(append([]string{}, hosts...), csr.DNSNames...)
+				for _, ip := range csr.IPAddresses {
+					hosts = append(hosts, ip.String())
+				}
+				if csr.Subject.CommonName != "" {
+					hosts = append(hosts, csr.Subject.CommonName)
+				}
+			}
+		}
+
+		for _, host := range hosts {
+			if !allowed(host) {
+				log.Warningf("filter: host %q for label %s is not in the SAN allowlist", host, label)
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// minRSAKeySize is the smallest RSA modulus, in bits, this filter will
+// accept; anything weaker is rejected regardless of profile.
+const minRSAKeySize = 2048
+
+// approvedCurves lists the elliptic curves NewKeyStrengthFilter accepts
+// for ECDSA keys.
+var approvedCurves = map[elliptic.Curve]bool{
+	elliptic.P256(): true,
+	elliptic.P384(): true,
+	elliptic.P521(): true,
+}
+
+// NewKeyStrengthFilter builds a filter that inspects the public key in the
+// request's CSR and rejects RSA keys under minRSAKeySize bits or ECDSA
+// keys on a curve outside approvedCurves.
+func NewKeyStrengthFilter() filter {
+	return func(label string, req *signer.SignRequest) bool {
+		block, _ := pem.Decode([]byte(req.Request))
+		if block == nil {
+			return false
+		}
+
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			return false
+		}
+
+		switch pub := csr.PublicKey.(type) {
+		case *rsa.PublicKey:
+			if pub.N.BitLen() < minRSAKeySize {
+				log.Warningf("filter: rejecting RSA key of %d bits for label %s", pub.N.BitLen(), label)
+				return false
+			}
+		case *ecdsa.PublicKey:
+			if !approvedCurves[pub.Curve] {
+				log.Warningf("filter: rejecting ECDSA key on unapproved curve for label %s", label)
+				return false
+			}
+		default:
+			log.Warningf("filter: rejecting unrecognised key type for label %s", label)
+			return false
+		}
+		return true
+	}
+}
+
+// NewMaxValidityFilter builds a filter that doesn't reject requests, but
+// clamps any requested expiry longer than max down to it by rewriting
+// req.NotAfter in place before s.Sign sees it.
+func NewMaxValidityFilter(max time.Duration) filter {
+	return func(label string, req *signer.SignRequest) bool {
+		limit := time.Now().Add(max)
+		if req.NotAfter.IsZero() || req.NotAfter.After(limit) {
+			req.NotAfter = limit
+		}
+		return true
+	}
+}
+
+// NewCommonNameRateLimitFilter builds a filter that rejects a signing
+// request once the request's Common Name has been seen more than limit
+// times within window.
+func NewCommonNameRateLimitFilter(limit int, window time.Duration) filter {
+	var mu sync.Mutex
+	seen := map[string][]time.Time{}
+
+	return func(label string, req *signer.SignRequest) bool {
+		cn := ""
+		if req.Subject != nil {
+			cn = req.Subject.CN
+		}
+		if cn == "" {
+			return true
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		cutoff := now.Add(-window)
+
+		recent := seen[cn][:0]
+		for _, t := range seen[cn] {
+			if t.After(cutoff) {
+				recent = append(recent, t)
+			}
+		}
+
+		if len(recent) >= limit {
+			log.Warningf("filter: rate limit exceeded for CN %q on label %s", cn, label)
+			seen[cn] = recent
+			return false
+		}
+
+		seen[cn] = append(recent, now)
+		return true
+	}
+}
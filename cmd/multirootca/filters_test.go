@@ -0,0 +1,106 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/cfssl/signer"
+)
+
+func TestSANAllowlistFilter(t *testing.T) {
+	f, err := NewSANAllowlistFilter(sanAllowlistConfig{
+		Literal: []string{"example.com"},
+		Regex:   []string{`^.*\.example\.com$`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		hosts []string
+		want  bool
+	}{
+		{[]string{"example.com"}, true},
+		{[]string{"foo.example.com"}, true},
+		{[]string{"example.com", "evil.com"}, false},
+		{[]string{"evil.org"}, false},
+	}
+
+	for _, c := range cases {
+		req := &signer.SignRequest{Hosts: c.hosts}
+		if got := f("default", req); got != c.want {
+			t.Errorf("hosts %v: got %v, want %v", c.hosts, got, c.want)
+		}
+	}
+}
+
+func TestSANAllowlistFilterChecksCSRNames(t *testing.T) {
+	f, err := NewSANAllowlistFilter(sanAllowlistConfig{Literal: []string{"example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csrPEM := mustCSR(t, "evil.com", []string{"evil.com"})
+
+	req := &signer.SignRequest{Request: csrPEM}
+	if f("default", req) {
+		t.Error("expected a CSR naming a host outside the allowlist to be rejected even with Hosts empty")
+	}
+}
+
+// mustCSR builds a PEM-encoded CSR for use in filter tests.
+func mustCSR(t *testing.T, cn string, dnsNames []string) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: cn},
+		DNSNames: dnsNames,
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, tpl, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func TestMaxValidityFilterClampsNotAfter(t *testing.T) {
+	f := NewMaxValidityFilter(24 * time.Hour)
+
+	req := &signer.SignRequest{NotAfter: time.Now().Add(365 * 24 * time.Hour)}
+	if !f("default", req) {
+		t.Fatal("expected filter to accept the request")
+	}
+
+	if req.NotAfter.After(time.Now().Add(25 * time.Hour)) {
+		t.Errorf("expected NotAfter to be clamped, got %v", req.NotAfter)
+	}
+}
+
+func TestCommonNameRateLimitFilter(t *testing.T) {
+	f := NewCommonNameRateLimitFilter(2, time.Minute)
+
+	req := &signer.SignRequest{Subject: &signer.Subject{CN: "test.example.com"}}
+
+	if !f("default", req) {
+		t.Error("first request should be allowed")
+	}
+	if !f("default", req) {
+		t.Error("second request should be allowed")
+	}
+	if f("default", req) {
+		t.Error("third request should be rate limited")
+	}
+}
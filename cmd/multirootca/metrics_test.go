@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestExpiryBucket(t *testing.T) {
+	cases := []struct {
+		days float64
+		want string
+	}{
+		{1, "0-7d"},
+		{7, "0-7d"},
+		{10, "7-30d"},
+		{60, "30-90d"},
+		{200, "90-365d"},
+		{400, "365d+"},
+	}
+
+	for _, c := range cases {
+		if got := expiryBucket(c.days); got != c.want {
+			t.Errorf("expiryBucket(%v) = %q, want %q", c.days, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloudflare/cfssl/helpers"
+	"github.com/cloudflare/cfssl/log"
+	"github.com/cloudflare/cfssl/ocsp"
+)
+
+// ocspStore holds the certificate status records published by dispatchRequest
+// after every successful signing operation. It is nil (and the /ocsp,
+// /revoke endpoints disabled) unless a status store is configured at
+// startup; see initOCSP.
+var ocspStore ocsp.StatusStore
+
+// ocspResponder serves OCSP requests out of ocspStore, using the delegated
+// OCSP signer registered for each label.
+var ocspResponder *ocsp.Responder
+
+// initOCSP wires up ocspStore and ocspResponder from the delegated OCSP
+// signers configured for each label. It is a no-op, leaving OCSP disabled,
+// if signers is empty.
+func initOCSP(store ocsp.StatusStore, signers map[string]*ocsp.DelegatedSigner) {
+	if len(signers) == 0 {
+		return
+	}
+	ocspStore = store
+	ocspResponder = ocsp.NewResponder(store, signers)
+}
+
+// publishStatus records the result of a successful signing operation in
+// ocspStore, so the OCSP responder can answer queries for it. It is called
+// by dispatchRequest right after s.Sign succeeds.
+func publishStatus(label string, cert []byte) {
+	if ocspStore == nil {
+		return
+	}
+
+	x509Cert, err := helpers.ParseCertificatePEM(cert)
+	if err != nil {
+		log.Errorf("ocsp: failed to parse signed certificate for status publication: %v", err)
+		return
+	}
+
+	delegated, ok := ocspResponder.Signers[label]
+	if !ok {
+		log.Errorf("ocsp: no delegated signer configured for label %s, cannot compute issuerKeyHash", label)
+		return
+	}
+	issuerKeyHash := hashIssuerPublicKey(delegated.Issuer)
+
+	err = ocspStore.Put(ocsp.Record{
+		Serial:        x509Cert.SerialNumber.String(),
+		IssuerKeyHash: issuerKeyHash,
+		CALabel:       label,
+		Status:        ocsp.StatusGood,
+		NotAfter:      x509Cert.NotAfter,
+	})
+	if err != nil {
+		log.Errorf("ocsp: failed to publish status for serial %s: %v", x509Cert.SerialNumber, err)
+	}
+}
+
+// subjectPublicKeyInfo mirrors the ASN.1 SubjectPublicKeyInfo structure,
+// letting us pull out the raw BIT STRING backing a certificate's public
+// key without re-deriving it from the typed crypto.PublicKey cfssl
+// normally works with.
+type subjectPublicKeyInfo struct {
+	Raw       asn1.RawContent
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// hashIssuerPublicKey computes RFC 6960's CertID.issuerKeyHash: the SHA-1
+// digest of the issuer's subjectPublicKey BIT STRING value, not the DER
+// of the issuer's distinguished name and not the whole SubjectPublicKeyInfo
+// (which would also cover the algorithm identifier).
+func hashIssuerPublicKey(issuer *x509.Certificate) []byte {
+	var info subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &info); err != nil {
+		log.Errorf("ocsp: failed to parse issuer public key info: %v", err)
+		return nil
+	}
+	sum := sha1.Sum(info.PublicKey.RightAlign())
+	return sum[:]
+}
+
+// ocspHandler answers an RFC 6960 OCSP request for the signing label named
+// by the "label" query parameter, e.g. POST /ocsp?label=default, falling
+// back to defaultLabel when none is given.
+func ocspHandler(w http.ResponseWriter, req *http.Request) {
+	if ocspResponder == nil {
+		fail(w, req, http.StatusNotImplemented, 1, "OCSP is not configured on this server", "")
+		return
+	}
+
+	label := req.URL.Query().Get("label")
+	if label == "" {
+		label = defaultLabel
+	}
+
+	ocspResponder.ServeHTTP(label, w, req)
+}
+
+// revokeRequest is the body expected by revokeHandler.
+type revokeRequest struct {
+	Serial string `json:"serial"`
+	Reason int    `json:"reason"`
+}
+
+// revokeHandler flips the status of a previously issued certificate to
+// revoked, so that subsequent OCSP queries for it return "revoked".
+func revokeHandler(w http.ResponseWriter, req *http.Request) {
+	if ocspStore == nil {
+		fail(w, req, http.StatusNotImplemented, 1, "OCSP is not configured on this server", "")
+		return
+	}
+
+	if req.Method != "POST" {
+		fail(w, req, http.StatusMethodNotAllowed, 1, "only POST is permitted", "")
+		return
+	}
+
+	var rr revokeRequest
+	if err := json.NewDecoder(req.Body).Decode(&rr); err != nil {
+		fail(w, req, http.StatusBadRequest, 1, err.Error(), "while unmarshaling revoke request")
+		return
+	}
+	defer req.Body.Close()
+
+	if rr.Serial == "" {
+		fail(w, req, http.StatusBadRequest, 1, "invalid request", "missing serial")
+		return
+	}
+
+	if err := ocspStore.Revoke(rr.Serial, rr.Reason); err != nil {
+		fail(w, req, http.StatusBadRequest, 1, err.Error(), "while revoking serial "+rr.Serial)
+		return
+	}
+	if ocspResponder != nil {
+		ocspResponder.Invalidate(rr.Serial)
+	}
+
+	log.Infof("revocation: requester=%s, serial=%s, reason=%s", req.RemoteAddr, rr.Serial, strconv.Itoa(rr.Reason))
+
+	w.Write([]byte(`{"success":true}`))
+}
+
+// startOCSPPreSigner launches a background worker that periodically
+// refreshes the responder's pre-signed response cache, so high-QPS
+// deployments can serve cached OCSP responses instead of signing on the
+// request path. It never returns.
+func startOCSPPreSigner(label string, interval time.Duration, serials func() []string) {
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		ocspResponder.PreSign(label, serials())
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cloudflare/cfssl/api"
+	"github.com/cloudflare/cfssl/auth"
+	"github.com/cloudflare/cfssl/csr"
+	"github.com/cloudflare/cfssl/log"
+	"github.com/cloudflare/cfssl/signer/local"
+)
+
+// rotationRequest is the body expected by renewHandler and rekeyHandler:
+// an authenticated envelope around a CSR template whose CN, if set,
+// replaces the CA's Common Name (and, for rekeyHandler, whose KeyRequest
+// describes the key to generate).
+type rotationRequest struct {
+	Label   string                  `json:"label"`
+	Request csr.CertificateRequest `json:"request"`
+}
+
+// RenewResponse is returned by the /ca/renew endpoint.
+type RenewResponse struct {
+	Certificate string `json:"certificate"`
+}
+
+// RekeyResponse is returned by the /ca/rekey endpoint.
+type RekeyResponse struct {
+	PrivateKey  string `json:"private_key"`
+	Certificate string `json:"certificate"`
+}
+
+// localSigner returns s as a *local.Signer, since RenewCA and Rekey are
+// only meaningful for a signer holding its own CA key in-process.
+func localSigner(label string) (*local.Signer, bool) {
+	s, ok := signers[label]
+	if !ok {
+		return nil, false
+	}
+	ls, ok := s.(*local.Signer)
+	return ls, ok
+}
+
+// authenticateRotation reads and authenticates an AuthenticatedRequest
+// body the same way dispatchRequest does, returning the decoded
+// rotationRequest.
+func authenticateRotation(w http.ResponseWriter, req *http.Request) (*rotationRequest, *local.Signer, bool) {
+	if req.Method != "POST" {
+		fail(w, req, http.StatusMethodNotAllowed, 1, "only POST is permitted", "")
+		return nil, nil, false
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		fail(w, req, http.StatusInternalServerError, 1, err.Error(), "while reading request body")
+		return nil, nil, false
+	}
+	defer req.Body.Close()
+
+	var authReq auth.AuthenticatedRequest
+	if err := json.Unmarshal(body, &authReq); err != nil {
+		fail(w, req, http.StatusBadRequest, 1, err.Error(), "while unmarshaling request body")
+		return nil, nil, false
+	}
+
+	var rr rotationRequest
+	if err := json.Unmarshal(authReq.Request, &rr); err != nil {
+		fail(w, req, http.StatusBadRequest, 1, err.Error(), "while unmarshalling authenticated request")
+		return nil, nil, false
+	}
+
+	if rr.Label == "" {
+		rr.Label = defaultLabel
+	}
+
+	s, ok := localSigner(rr.Label)
+	if !ok {
+		fail(w, req, http.StatusBadRequest, 1, "bad request", "label "+rr.Label+" has no in-process CA key to rotate")
+		return nil, nil, false
+	}
+
+	policy := s.Policy()
+	if policy == nil || policy.Default == nil || policy.Default.Provider == nil {
+		fail(w, req, http.StatusUnauthorized, 1, "authorisation required", "label "+rr.Label+" has no authentication provider configured")
+		return nil, nil, false
+	}
+
+	if !policy.Default.Provider.Verify(&authReq) {
+		fail(w, req, http.StatusBadRequest, 1, "invalid token", "received authenticated request with invalid token")
+		return nil, nil, false
+	}
+
+	return &rr, s, true
+}
+
+// renewHandler implements POST /api/v1/cfssl/ca/renew.
+func renewHandler(w http.ResponseWriter, req *http.Request) {
+	rr, s, ok := authenticateRotation(w, req)
+	if !ok {
+		return
+	}
+
+	cert, err := s.RenewCA(&rr.Request)
+	if err != nil {
+		fail(w, req, http.StatusInternalServerError, 1, "renewal failed", err.Error())
+		return
+	}
+
+	log.Infof("ca renewal: requester=%s, label=%s", req.RemoteAddr, rr.Label)
+
+	res := api.NewSuccessResponse(&RenewResponse{Certificate: string(cert)})
+	json.NewEncoder(w).Encode(res)
+}
+
+// rekeyHandler implements POST /api/v1/cfssl/ca/rekey.
+func rekeyHandler(w http.ResponseWriter, req *http.Request) {
+	rr, s, ok := authenticateRotation(w, req)
+	if !ok {
+		return
+	}
+
+	newKey, newCert, err := s.Rekey(&rr.Request)
+	if err != nil {
+		fail(w, req, http.StatusInternalServerError, 1, "rekey failed", err.Error())
+		return
+	}
+
+	log.Infof("ca rekey: requester=%s, label=%s", req.RemoteAddr, rr.Label)
+
+	res := api.NewSuccessResponse(&RekeyResponse{PrivateKey: string(newKey), Certificate: string(newCert)})
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/cloudflare/cfssl/config"
+	"github.com/cloudflare/cfssl/signer"
+	"github.com/cloudflare/cfssl/signer/local"
+)
+
+// backendSchemes lists the URI schemes newLabelSigner recognises as
+// naming a CryptoBackend rather than a filesystem key path, so a label's
+// "key" config value of e.g. "pkcs11:token=ca;object=root-key" is routed
+// to local.NewSignerFromURI instead of local.NewSignerFromFile.
+var backendSchemes = []string{"pkcs11:", "awskms:", "gcpkms:", "azurekv:"}
+
+// newLabelSigner builds the signer.Signer for one CA server label. key is
+// either a path to a PEM private key file, or a backend URI naming a
+// KMS/HSM-held key (see signer/kms). certFile is ignored for backend URIs:
+// the certificate must come from the backend itself, so only a backend
+// that stores one, such as the bundled PKCS#11 implementation, can back a
+// label; AWS/GCP KMS and Azure Key Vault URIs fail to build a signer.
+func newLabelSigner(certFile, key string, policy *config.Signing) (signer.Signer, error) {
+	for _, scheme := range backendSchemes {
+		if strings.HasPrefix(key, scheme) {
+			return local.NewSignerFromURI(key, policy)
+		}
+	}
+
+	return local.NewSignerFromFile(certFile, key, policy)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer emits spans around the stages of dispatchRequest (auth.Verify,
+// signer.Sign, helpers.ParseCertificatePEM) so a reverse proxy that
+// propagates W3C traceparent headers can stitch CA signing into a wider
+// request trace.
+var tracer = otel.Tracer("github.com/cloudflare/cfssl/cmd/multirootca")
+
+// propagator extracts an incoming W3C traceparent header, if any, so spans
+// started for this request are children of the caller's trace rather than
+// roots of their own.
+var propagator = propagation.TraceContext{}
+
+// startSpan starts a child span named name from req's propagated trace
+// context. The caller is responsible for calling span.End().
+func startSpan(req *http.Request, name string) (*http.Request, trace.Span) {
+	ctx := propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	ctx, span := tracer.Start(ctx, name)
+	return req.WithContext(ctx), span
+}
@@ -0,0 +1,93 @@
+package ocsp
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+var statusBucket = []byte("ocsp-status")
+
+// BoltStore is a StatusStore backed by a local BoltDB file. It is intended
+// for single-node deployments where pulling in a full SQL dependency is
+// overkill.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB database at path and
+// returns a StatusStore backed by it.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(statusBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Put implements StatusStore.
+func (s *BoltStore) Put(r Record) error {
+	buf, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(statusBucket).Put([]byte(r.Serial), buf)
+	})
+}
+
+// Get implements StatusStore.
+func (s *BoltStore) Get(serial string) (Record, error) {
+	var r Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		buf := tx.Bucket(statusBucket).Get([]byte(serial))
+		if buf == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(buf, &r)
+	})
+	return r, err
+}
+
+// Revoke implements StatusStore.
+func (s *BoltStore) Revoke(serial string, reason int) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(statusBucket)
+		buf := b.Get([]byte(serial))
+		if buf == nil {
+			return ErrNotFound
+		}
+
+		var r Record
+		if err := json.Unmarshal(buf, &r); err != nil {
+			return err
+		}
+
+		r.Status = StatusRevoked
+		r.Reason = reason
+		r.RevokedAt = time.Now()
+
+		out, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(serial), out)
+	})
+}
@@ -0,0 +1,31 @@
+package ocsp
+
+import (
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// readOCSPRequest extracts the DER-encoded OCSP request from an HTTP
+// request, supporting both the POST form (RFC 6960 §A.1, body is the
+// request) and the GET form (§A.1.1, base64 request in the URL path).
+func readOCSPRequest(req *http.Request) ([]byte, error) {
+	switch req.Method {
+	case http.MethodPost:
+		return ioutil.ReadAll(req.Body)
+	case http.MethodGet:
+		encoded := strings.TrimPrefix(req.URL.Path, "/")
+		return base64.StdEncoding.DecodeString(encoded)
+	default:
+		return nil, errors.New("ocsp: unsupported HTTP method " + req.Method)
+	}
+}
+
+func mustSerial(s string) *big.Int {
+	n := new(big.Int)
+	n.SetString(s, 10)
+	return n
+}
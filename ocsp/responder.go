@@ -0,0 +1,160 @@
+package ocsp
+
+import (
+	"crypto"
+	"crypto/x509"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudflare/cfssl/log"
+	"golang.org/x/crypto/ocsp"
+)
+
+// DelegatedSigner is the key material the responder uses to sign OCSP
+// responses on behalf of a CA, per RFC 6960 §4.2.2.2. Responder may sign
+// with the CA's own key, or with a short-lived certificate the CA has
+// delegated solely for OCSP signing.
+type DelegatedSigner struct {
+	Cert   *x509.Certificate
+	Issuer *x509.Certificate
+	Key    crypto.Signer
+}
+
+// Responder answers RFC 6960 OCSP requests for one or more signing labels,
+// each of which has its own DelegatedSigner and shares the same
+// StatusStore.
+type Responder struct {
+	Store   StatusStore
+	Signers map[string]*DelegatedSigner
+
+	cacheMu sync.RWMutex
+	cache   map[string][]byte
+}
+
+// NewResponder constructs a Responder over store, keyed by CA label.
+func NewResponder(store StatusStore, signers map[string]*DelegatedSigner) *Responder {
+	return &Responder{
+		Store:   store,
+		Signers: signers,
+		cache:   map[string][]byte{},
+	}
+}
+
+// ServeHTTP answers an OCSP request for label (the CA whose delegated
+// signer should be used). Because of the extra label argument, Responder
+// does not itself satisfy http.Handler; callers wrap it in a handler that
+// picks the label.
+func (r *Responder) ServeHTTP(label string, w http.ResponseWriter, req *http.Request) {
+	body, err := readOCSPRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ocspReq, err := ocsp.ParseRequest(body)
+	if err != nil {
+		http.Error(w, "malformed OCSP request", http.StatusBadRequest)
+		return
+	}
+
+	signer, ok := r.Signers[label]
+	if !ok {
+		http.Error(w, "no OCSP signer configured for label "+label, http.StatusInternalServerError)
+		return
+	}
+
+	if cached, ok := r.cached(label, ocspReq.SerialNumber.String()); ok {
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(cached)
+		return
+	}
+
+	resp, err := r.sign(signer, ocspReq.SerialNumber.String())
+	if err != nil {
+		log.Errorf("ocsp: failed to build response: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	w.Write(resp)
+}
+
+func (r *Responder) cached(label, serial string) ([]byte, bool) {
+	r.cacheMu.RLock()
+	defer r.cacheMu.RUnlock()
+	resp, ok := r.cache[label+"|"+serial]
+	return resp, ok
+}
+
+// Invalidate drops any pre-signed response cached for serial, under every
+// label this Responder knows about. Callers must invoke this after a
+// StatusStore.Revoke for the same serial, since the cache is populated
+// independently of the store by PreSign and otherwise keeps serving a
+// stale "good" response until it is next refreshed or the process
+// restarts.
+func (r *Responder) Invalidate(serial string) {
+	r.cacheMu.Lock()
+	defer r.cacheMu.Unlock()
+	for label := range r.Signers {
+		delete(r.cache, label+"|"+serial)
+	}
+}
+
+// sign looks up serial in the status store and produces a freshly signed
+// OCSP response for it.
+func (r *Responder) sign(signer *DelegatedSigner, serial string) ([]byte, error) {
+	rec, err := r.Store.Get(serial)
+	if err != nil {
+		if err == ErrNotFound {
+			return ocsp.CreateResponse(signer.Issuer, signer.Cert, ocsp.Response{
+				Status:       ocsp.Unknown,
+				SerialNumber: mustSerial(serial),
+				ThisUpdate:   time.Now(),
+			}, signer.Key)
+		}
+		return nil, err
+	}
+
+	status := ocsp.Good
+	var revokedAt time.Time
+	var reason int
+	if rec.Status == StatusRevoked {
+		status = ocsp.Revoked
+		revokedAt = rec.RevokedAt
+		reason = rec.Reason
+	}
+
+	return ocsp.CreateResponse(signer.Issuer, signer.Cert, ocsp.Response{
+		Status:           status,
+		SerialNumber:     mustSerial(serial),
+		RevokedAt:        revokedAt,
+		RevocationReason: reason,
+		ThisUpdate:       time.Now(),
+		NextUpdate:       time.Now().Add(4 * time.Hour),
+	}, signer.Key)
+}
+
+// PreSign populates label's response cache for each of serials, signing
+// each one from its current record in the store. It is meant to be run periodically by a background worker so
+// high-QPS deployments can serve cached, pre-signed responses instead of
+// signing on the critical path of every request.
+func (r *Responder) PreSign(label string, serials []string) {
+	signer, ok := r.Signers[label]
+	if !ok {
+		return
+	}
+
+	for _, serial := range serials {
+		resp, err := r.sign(signer, serial)
+		if err != nil {
+			log.Warningf("ocsp: pre-sign failed for serial %s: %v", serial, err)
+			continue
+		}
+
+		r.cacheMu.Lock()
+		r.cache[label+"|"+serial] = resp
+		r.cacheMu.Unlock()
+	}
+}
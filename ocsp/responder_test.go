@@ -0,0 +1,118 @@
+package ocsp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+func newTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func newTestResponder(t *testing.T) (*Responder, StatusStore) {
+	ca, key := newTestCA(t)
+	store := newTestBoltStore(t)
+
+	responder := NewResponder(store, map[string]*DelegatedSigner{
+		"default": {Cert: ca, Issuer: ca, Key: key},
+	})
+	return responder, store
+}
+
+func TestResponderGoodRevokedUnknown(t *testing.T) {
+	responder, store := newTestResponder(t)
+	ca := responder.Signers["default"].Cert
+
+	good := big.NewInt(100)
+	revoked := big.NewInt(200)
+
+	if err := store.Put(Record{Serial: good.String(), Status: StatusGood, NotAfter: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(Record{Serial: revoked.String(), Status: StatusGood, NotAfter: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Revoke(revoked.String(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	signer := responder.Signers["default"]
+
+	for _, tc := range []struct {
+		serial *big.Int
+		want   int
+	}{
+		{good, ocsp.Good},
+		{revoked, ocsp.Revoked},
+		{big.NewInt(300), ocsp.Unknown},
+	} {
+		der, err := responder.sign(signer, tc.serial.String())
+		if err != nil {
+			t.Fatalf("serial %s: %v", tc.serial, err)
+		}
+
+		resp, err := ocsp.ParseResponse(der, ca)
+		if err != nil {
+			t.Fatalf("serial %s: parsing response: %v", tc.serial, err)
+		}
+
+		if resp.Status != tc.want {
+			t.Errorf("serial %s: expected status %d, got %d", tc.serial, tc.want, resp.Status)
+		}
+	}
+}
+
+func TestResponderInvalidateDropsPreSignedCache(t *testing.T) {
+	responder, store := newTestResponder(t)
+
+	serial := big.NewInt(100)
+	if err := store.Put(Record{Serial: serial.String(), Status: StatusGood, NotAfter: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	responder.PreSign("default", []string{serial.String()})
+	if _, ok := responder.cached("default", serial.String()); !ok {
+		t.Fatal("expected PreSign to populate the cache")
+	}
+
+	if err := store.Revoke(serial.String(), 1); err != nil {
+		t.Fatal(err)
+	}
+	responder.Invalidate(serial.String())
+
+	if _, ok := responder.cached("default", serial.String()); ok {
+		t.Error("expected Invalidate to drop the pre-signed cache entry after revocation")
+	}
+}
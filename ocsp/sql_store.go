@@ -0,0 +1,78 @@
+package ocsp
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SQLStore is a StatusStore backed by a SQL database, reached through the
+// standard library's database/sql. Its queries use $N placeholders and an
+// INSERT ... ON CONFLICT upsert, so the driver must accept both (e.g.
+// "postgres" or "sqlite3"; MySQL's syntax differs). The caller is
+// responsible for opening db with the driver of their choice and for
+// having created the ocsp_status table (see sql_store.sql).
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore wraps an already-open *sql.DB as a StatusStore.
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// Put implements StatusStore.
+func (s *SQLStore) Put(r Record) error {
+	_, err := s.db.Exec(
+		`INSERT INTO ocsp_status (serial, issuer_key_hash, ca_label, status, reason, revoked_at, not_after)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (serial) DO UPDATE SET
+			issuer_key_hash = excluded.issuer_key_hash,
+			ca_label = excluded.ca_label,
+			status = excluded.status,
+			reason = excluded.reason,
+			revoked_at = excluded.revoked_at,
+			not_after = excluded.not_after`,
+		r.Serial, r.IssuerKeyHash, r.CALabel, r.Status, r.Reason, r.RevokedAt, r.NotAfter)
+	return err
+}
+
+// Get implements StatusStore.
+func (s *SQLStore) Get(serial string) (Record, error) {
+	var r Record
+	var revokedAt, notAfter sql.NullTime
+
+	row := s.db.QueryRow(
+		`SELECT serial, issuer_key_hash, ca_label, status, reason, revoked_at, not_after
+		 FROM ocsp_status WHERE serial = $1`, serial)
+
+	err := row.Scan(&r.Serial, &r.IssuerKeyHash, &r.CALabel, &r.Status, &r.Reason, &revokedAt, &notAfter)
+	if err == sql.ErrNoRows {
+		return r, ErrNotFound
+	}
+	if err != nil {
+		return r, err
+	}
+
+	r.RevokedAt = revokedAt.Time
+	r.NotAfter = notAfter.Time
+	return r, nil
+}
+
+// Revoke implements StatusStore.
+func (s *SQLStore) Revoke(serial string, reason int) error {
+	res, err := s.db.Exec(
+		`UPDATE ocsp_status SET status = $1, reason = $2, revoked_at = $3 WHERE serial = $4`,
+		StatusRevoked, reason, time.Now(), serial)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
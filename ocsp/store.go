@@ -0,0 +1,49 @@
+// Package ocsp implements a minimal OCSP (RFC 6960) responder on top of a
+// pluggable status store. It is used by cfssl's signing servers to answer
+// OCSP queries for certificates they have issued, without requiring an
+// external OCSP infrastructure.
+package ocsp
+
+import (
+	"errors"
+	"time"
+)
+
+// Status codes recorded for a certificate. These mirror the handful of
+// states the OCSP protocol itself can express.
+const (
+	StatusGood    = "good"
+	StatusRevoked = "revoked"
+)
+
+// ErrNotFound is returned by a StatusStore when no record exists for the
+// requested serial number.
+var ErrNotFound = errors.New("ocsp: no record for serial")
+
+// Record is the per-certificate status published by the signer after a
+// successful signing operation, and consulted by the responder when
+// answering an OCSP request.
+type Record struct {
+	Serial        string
+	IssuerKeyHash []byte
+	CALabel       string
+	Status        string
+	Reason        int
+	RevokedAt     time.Time
+	NotAfter      time.Time
+}
+
+// StatusStore is implemented by anything that can durably record and
+// retrieve certificate status. The signing server calls Put once per
+// issued certificate; the responder calls Get for every OCSP request it
+// serves; Revoke is invoked by the /revoke endpoint.
+type StatusStore interface {
+	// Put records the initial status of a newly issued certificate.
+	Put(Record) error
+	// Get returns the current record for serial, or ErrNotFound if the
+	// certificate is unknown to the store.
+	Get(serial string) (Record, error)
+	// Revoke flips the status of serial to revoked with the given
+	// RFC 5280 reason code.
+	Revoke(serial string, reason int) error
+}
@@ -0,0 +1,91 @@
+package ocsp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestBoltStore(t *testing.T) *BoltStore {
+	dir, err := ioutil.TempDir("", "ocsp-bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewBoltStore(filepath.Join(dir, "status.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		s.Close()
+		os.RemoveAll(dir)
+	})
+	return s
+}
+
+func TestBoltStoreRoundTrip(t *testing.T) {
+	s := newTestBoltStore(t)
+
+	rec := Record{
+		Serial:   "123456",
+		CALabel:  "default",
+		Status:   StatusGood,
+		NotAfter: time.Now().Add(24 * time.Hour),
+	}
+
+	if err := s.Put(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Get(rec.Serial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != StatusGood {
+		t.Errorf("expected status %q, got %q", StatusGood, got.Status)
+	}
+}
+
+func TestBoltStoreUnknownSerial(t *testing.T) {
+	s := newTestBoltStore(t)
+
+	if _, err := s.Get("does-not-exist"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestBoltStoreRevoke(t *testing.T) {
+	s := newTestBoltStore(t)
+
+	rec := Record{Serial: "987654", Status: StatusGood, NotAfter: time.Now().Add(24 * time.Hour)}
+	if err := s.Put(rec); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Revoke(rec.Serial, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Get(rec.Serial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != StatusRevoked {
+		t.Errorf("expected status %q after revoke, got %q", StatusRevoked, got.Status)
+	}
+	if got.Reason != 1 {
+		t.Errorf("expected reason 1, got %d", got.Reason)
+	}
+}
+
+func TestBoltStoreRevokeUnknownSerial(t *testing.T) {
+	s := newTestBoltStore(t)
+
+	if err := s.Revoke("does-not-exist", 1); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+//go:build awskms
+// +build awskms
+
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+func init() {
+	Register("awskms", openAWSKMS)
+}
+
+// awsKMSBackend signs through an AWS KMS asymmetric key, addressed by ARN.
+type awsKMSBackend struct {
+	client    *kms.Client
+	keyID     string
+	supported []kmstypes.SigningAlgorithmSpec
+	pub       crypto.PublicKey
+}
+
+// openAWSKMS parses "awskms:///<key-arn>" and resolves the signing
+// algorithm from the key's metadata.
+func openAWSKMS(uri *url.URL) (CryptoBackend, error) {
+	keyID := strings.TrimPrefix(uri.Path, "/")
+	if keyID == "" {
+		return nil, fmt.Errorf("awskms: key ARN is required")
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("awskms: loading AWS config: %w", err)
+	}
+
+	client := kms.NewFromConfig(cfg)
+
+	pubResp, err := client.GetPublicKey(context.Background(), &kms.GetPublicKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, fmt.Errorf("awskms: GetPublicKey: %w", err)
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(pubResp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("awskms: parsing public key: %w", err)
+	}
+
+	return &awsKMSBackend{client: client, keyID: keyID, supported: pubResp.SigningAlgorithms, pub: pub}, nil
+}
+
+// signingAlgoFor picks the KMS SigningAlgorithmSpec matching the key type
+// and the hash the caller actually pre-digested with - a P-384/P-521 CA
+// key or an RSA profile using SHA-384/512 sends a digest KMS would
+// reject (or silently mis-verify) against a hard-coded SHA-256 spec.
+func signingAlgoFor(pub crypto.PublicKey, opts crypto.SignerOpts, supported []kmstypes.SigningAlgorithmSpec) (kmstypes.SigningAlgorithmSpec, error) {
+	_, isECDSA := pub.(*ecdsa.PublicKey)
+
+	var want kmstypes.SigningAlgorithmSpec
+	switch opts.HashFunc() {
+	case crypto.SHA384:
+		if isECDSA {
+			want = kmstypes.SigningAlgorithmSpecEcdsaSha384
+		} else {
+			want = kmstypes.SigningAlgorithmSpecRsassaPkcs1V15Sha384
+		}
+	case crypto.SHA512:
+		if isECDSA {
+			want = kmstypes.SigningAlgorithmSpecEcdsaSha512
+		} else {
+			want = kmstypes.SigningAlgorithmSpecRsassaPkcs1V15Sha512
+		}
+	default:
+		if isECDSA {
+			want = kmstypes.SigningAlgorithmSpecEcdsaSha256
+		} else {
+			want = kmstypes.SigningAlgorithmSpecRsassaPkcs1V15Sha256
+		}
+	}
+
+	for _, s := range supported {
+		if s == want {
+			return want, nil
+		}
+	}
+	return "", fmt.Errorf("awskms: key does not support signing algorithm %s", want)
+}
+
+// Signer implements CryptoBackend.
+func (b *awsKMSBackend) Signer() (crypto.Signer, error) {
+	return &awsKMSSigner{backend: b}, nil
+}
+
+// Certificate implements CryptoBackend. AWS KMS does not store
+// certificates alongside keys, so Certificate always returns an error and
+// local.NewSignerFromBackend cannot build a Signer from this backend.
+func (b *awsKMSBackend) Certificate() (*x509.Certificate, error) {
+	return nil, fmt.Errorf("awskms: backend does not store a certificate for key %s; configure one separately", b.keyID)
+}
+
+type awsKMSSigner struct {
+	backend *awsKMSBackend
+}
+
+func (s *awsKMSSigner) Public() crypto.PublicKey {
+	return s.backend.pub
+}
+
+func (s *awsKMSSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	spec, err := signingAlgoFor(s.backend.pub, opts, s.backend.supported)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.backend.client.Sign(context.Background(), &kms.SignInput{
+		KeyId:            aws.String(s.backend.keyID),
+		Message:          digest,
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: spec,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("awskms: Sign: %w", err)
+	}
+	return out.Signature, nil
+}
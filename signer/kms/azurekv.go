@@ -0,0 +1,113 @@
+//go:build azurekv
+// +build azurekv
+
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+)
+
+func init() {
+	Register("azurekv", openAzureKV)
+}
+
+// azureKVBackend signs through an Azure Key Vault key, addressed by
+// "azurekv://<vault-name>.vault.azure.net/keys/<key-name>[/<version>]".
+type azureKVBackend struct {
+	client  *azkeys.Client
+	keyName string
+	version string
+	pub     crypto.PublicKey
+}
+
+func openAzureKV(uri *url.URL) (CryptoBackend, error) {
+	parts := strings.Split(strings.Trim(uri.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "keys" {
+		return nil, fmt.Errorf("azurekv: expected path /keys/<name>[/<version>], got %q", uri.Path)
+	}
+	keyName := parts[1]
+	version := ""
+	if len(parts) > 2 {
+		version = parts[2]
+	}
+
+	vaultURL := "https://" + uri.Host
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("azurekv: acquiring credentials: %w", err)
+	}
+
+	client, err := azkeys.NewClient(vaultURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("azurekv: creating client: %w", err)
+	}
+
+	resp, err := client.GetKey(context.Background(), keyName, version, nil)
+	if err != nil {
+		return nil, fmt.Errorf("azurekv: GetKey: %w", err)
+	}
+
+	pub, err := publicKeyFromJWK(resp.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &azureKVBackend{client: client, keyName: keyName, version: version, pub: pub}, nil
+}
+
+// Signer implements CryptoBackend.
+func (b *azureKVBackend) Signer() (crypto.Signer, error) {
+	return &azureKVSigner{backend: b}, nil
+}
+
+// Certificate implements CryptoBackend. This backend reads only the Key
+// Vault key, not any certificate object, so Certificate always returns an
+// error and local.NewSignerFromBackend cannot build a Signer from it.
+func (b *azureKVBackend) Certificate() (*x509.Certificate, error) {
+	return nil, fmt.Errorf("azurekv: backend does not store a certificate for key %s; configure one separately", b.keyName)
+}
+
+type azureKVSigner struct {
+	backend *azureKVBackend
+}
+
+func (s *azureKVSigner) Public() crypto.PublicKey {
+	return s.backend.pub
+}
+
+func (s *azureKVSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	alg := signAlgorithmFor(s.backend.pub, opts)
+
+	resp, err := s.backend.client.Sign(context.Background(), s.backend.keyName, s.backend.version, azkeys.SignParameters{
+		Algorithm: &alg,
+		Value:     digest,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("azurekv: Sign: %w", err)
+	}
+
+	sig := resp.Result
+	if _, ok := s.backend.pub.(*ecdsa.PublicKey); ok {
+		// Key Vault returns ECDSA signatures as r||s; crypto.Signer
+		// callers expect the ASN.1 DER form.
+		half := len(sig) / 2
+		return asn1.Marshal(struct{ R, S *big.Int }{
+			new(big.Int).SetBytes(sig[:half]),
+			new(big.Int).SetBytes(sig[half:]),
+		})
+	}
+	return sig, nil
+}
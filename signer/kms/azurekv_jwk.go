@@ -0,0 +1,85 @@
+//go:build azurekv
+// +build azurekv
+
+package kms
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+)
+
+// publicKeyFromJWK converts the JSON Web Key Key Vault returns for a key
+// into the standard library's crypto.PublicKey types.
+func publicKeyFromJWK(jwk *azkeys.JSONWebKey) (crypto.PublicKey, error) {
+	if jwk == nil {
+		return nil, fmt.Errorf("azurekv: key has no public material")
+	}
+
+	switch {
+	case jwk.N != nil && jwk.E != nil:
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(jwk.N),
+			E: int(new(big.Int).SetBytes(jwk.E).Int64()),
+		}, nil
+	case jwk.X != nil && jwk.Y != nil:
+		curve, err := curveFor(jwk.Crv)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(jwk.X),
+			Y:     new(big.Int).SetBytes(jwk.Y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("azurekv: unsupported key type")
+	}
+}
+
+func curveFor(crv *azkeys.JSONWebKeyCurveName) (elliptic.Curve, error) {
+	if crv == nil {
+		return nil, fmt.Errorf("azurekv: key is missing its curve name")
+	}
+	switch *crv {
+	case azkeys.JSONWebKeyCurveNameP256:
+		return elliptic.P256(), nil
+	case azkeys.JSONWebKeyCurveNameP384:
+		return elliptic.P384(), nil
+	case azkeys.JSONWebKeyCurveNameP521:
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("azurekv: unsupported curve %q", *crv)
+	}
+}
+
+// signAlgorithmFor picks the Key Vault JSONWebKeySignatureAlgorithm
+// matching the key type and the hash the caller pre-digested with. RSA
+// keys sign with PKCS#1 v1.5 (RS*), the padding the SHA*WithRSA
+// signature algorithms cfssl selects for them expect.
+func signAlgorithmFor(pub crypto.PublicKey, opts crypto.SignerOpts) azkeys.JSONWebKeySignatureAlgorithm {
+	_, isECDSA := pub.(*ecdsa.PublicKey)
+
+	switch opts.HashFunc() {
+	case crypto.SHA384:
+		if isECDSA {
+			return azkeys.JSONWebKeySignatureAlgorithmES384
+		}
+		return azkeys.JSONWebKeySignatureAlgorithmRS384
+	case crypto.SHA512:
+		if isECDSA {
+			return azkeys.JSONWebKeySignatureAlgorithmES512
+		}
+		return azkeys.JSONWebKeySignatureAlgorithmRS512
+	default:
+		if isECDSA {
+			return azkeys.JSONWebKeySignatureAlgorithmES256
+		}
+		return azkeys.JSONWebKeySignatureAlgorithmRS256
+	}
+}
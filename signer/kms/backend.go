@@ -0,0 +1,59 @@
+// Package kms lets a signer reach for its private key through a remote or
+// hardware-backed service rather than holding the raw key bytes in the CA
+// server's own process. A CryptoBackend is opened from a URI naming both
+// the kind of backend and the key to use within it, e.g.:
+//
+//	pkcs11:token=ca;object=root-key
+//	awskms:///arn:aws:kms:us-east-1:111111111111:key/1234abcd-...
+//	gcpkms:///projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1
+//	azurekv://my-vault.vault.azure.net/keys/root-key
+package kms
+
+import (
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+)
+
+// CryptoBackend is implemented by each supported KMS/HSM integration. It
+// hands back a crypto.Signer that performs the raw signing operation
+// remotely, plus the certificate associated with that key, without ever
+// exposing the private key material to the caller. Only that one leaf
+// certificate is returned; no intermediate chain is carried.
+type CryptoBackend interface {
+	// Signer returns a crypto.Signer whose private operations are
+	// delegated to the backend.
+	Signer() (crypto.Signer, error)
+	// Certificate returns the leaf certificate for the backend's key.
+	Certificate() (*x509.Certificate, error)
+}
+
+// Opener constructs a CryptoBackend from a parsed URI. Each backend
+// implementation registers itself under its URI scheme via Register.
+type Opener func(uri *url.URL) (CryptoBackend, error)
+
+var openers = map[string]Opener{}
+
+// Register associates scheme (the URI scheme used to select this backend,
+// e.g. "pkcs11") with an Opener. It is expected to be called from the
+// init function of each backend implementation.
+func Register(scheme string, open Opener) {
+	openers[scheme] = open
+}
+
+// Open parses uri and dispatches to the CryptoBackend registered for its
+// scheme.
+func Open(uri string) (CryptoBackend, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("kms: invalid backend URI %q: %w", uri, err)
+	}
+
+	open, ok := openers[parsed.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("kms: no backend registered for scheme %q", parsed.Scheme)
+	}
+
+	return open(parsed)
+}
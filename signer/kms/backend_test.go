@@ -0,0 +1,39 @@
+package kms
+
+import (
+	"crypto"
+	"crypto/x509"
+	"net/url"
+	"testing"
+)
+
+type fakeBackend struct{}
+
+func (fakeBackend) Signer() (crypto.Signer, error)          { return nil, nil }
+func (fakeBackend) Certificate() (*x509.Certificate, error) { return nil, nil }
+
+func TestOpenDispatchesByScheme(t *testing.T) {
+	Register("fake", func(uri *url.URL) (CryptoBackend, error) {
+		return fakeBackend{}, nil
+	})
+
+	backend, err := Open("fake:///whatever")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend == nil {
+		t.Fatal("expected a non-nil backend")
+	}
+}
+
+func TestOpenUnknownScheme(t *testing.T) {
+	if _, err := Open("nonexistent:///whatever"); err == nil {
+		t.Fatal("expected an error for an unregistered scheme")
+	}
+}
+
+func TestOpenInvalidURI(t *testing.T) {
+	if _, err := Open("://not a uri"); err == nil {
+		t.Fatal("expected an error for a malformed URI")
+	}
+}
@@ -0,0 +1,108 @@
+//go:build gcpkms
+// +build gcpkms
+
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+)
+
+func init() {
+	Register("gcpkms", openGCPKMS)
+}
+
+// gcpKMSBackend signs through a Cloud KMS asymmetric CryptoKeyVersion,
+// addressed by its full resource name.
+type gcpKMSBackend struct {
+	client        *kms.KeyManagementClient
+	keyVersion    string
+	pub           crypto.PublicKey
+	signAlgorithm kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+}
+
+// openGCPKMS parses "gcpkms:///<cryptoKeyVersion resource name>".
+func openGCPKMS(uri *url.URL) (CryptoBackend, error) {
+	keyVersion := strings.TrimPrefix(uri.Path, "/")
+	if keyVersion == "" {
+		return nil, fmt.Errorf("gcpkms: cryptoKeyVersion resource name is required")
+	}
+
+	ctx := context.Background()
+	client, err := kms.NewKeyManagementClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gcpkms: creating client: %w", err)
+	}
+
+	resp, err := client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: keyVersion})
+	if err != nil {
+		return nil, fmt.Errorf("gcpkms: GetPublicKey: %w", err)
+	}
+
+	block, _ := pem.Decode([]byte(resp.Pem))
+	if block == nil {
+		return nil, fmt.Errorf("gcpkms: public key response was not PEM-encoded")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("gcpkms: parsing public key: %w", err)
+	}
+
+	return &gcpKMSBackend{client: client, keyVersion: keyVersion, pub: pub, signAlgorithm: resp.Algorithm}, nil
+}
+
+// Signer implements CryptoBackend.
+func (b *gcpKMSBackend) Signer() (crypto.Signer, error) {
+	return &gcpKMSSigner{backend: b}, nil
+}
+
+// Certificate implements CryptoBackend. Cloud KMS does not store
+// certificates alongside keys, so Certificate always returns an error and
+// local.NewSignerFromBackend cannot build a Signer from this backend.
+func (b *gcpKMSBackend) Certificate() (*x509.Certificate, error) {
+	return nil, fmt.Errorf("gcpkms: backend does not store a certificate for key %s; configure one separately", b.keyVersion)
+}
+
+type gcpKMSSigner struct {
+	backend *gcpKMSBackend
+}
+
+func (s *gcpKMSSigner) Public() crypto.PublicKey {
+	return s.backend.pub
+}
+
+func (s *gcpKMSSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	req := &kmspb.AsymmetricSignRequest{
+		Name:   s.backend.keyVersion,
+		Digest: digestFor(opts, digest),
+	}
+
+	resp, err := s.backend.client.AsymmetricSign(context.Background(), req)
+	if err != nil {
+		return nil, fmt.Errorf("gcpkms: AsymmetricSign: %w", err)
+	}
+	return resp.Signature, nil
+}
+
+// digestFor wraps digest in the oneof Digest message AsymmetricSign
+// expects, keyed off the hash algorithm the caller signed with.
+func digestFor(opts crypto.SignerOpts, digest []byte) *kmspb.Digest {
+	switch opts.HashFunc() {
+	case crypto.SHA384:
+		return &kmspb.Digest{Digest: &kmspb.Digest_Sha384{Sha384: digest}}
+	case crypto.SHA512:
+		return &kmspb.Digest{Digest: &kmspb.Digest_Sha512{Sha512: digest}}
+	default:
+		return &kmspb.Digest{Digest: &kmspb.Digest_Sha256{Sha256: digest}}
+	}
+}
@@ -0,0 +1,231 @@
+//go:build pkcs11
+// +build pkcs11
+
+package kms
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/miekg/pkcs11"
+)
+
+func init() {
+	Register("pkcs11", openPKCS11)
+}
+
+// pkcs11Backend signs through a PKCS#11 token, e.g. a SoftHSM slot or a
+// hardware HSM. Opaque object handles are looked up by label at Open time
+// and reused for every signing operation.
+type pkcs11Backend struct {
+	ctx              *pkcs11.Ctx
+	session          pkcs11.SessionHandle
+	keyLabel         string
+	privateKeyHandle pkcs11.ObjectHandle
+	pub              crypto.PublicKey
+	cert             *x509.Certificate
+}
+
+// openPKCS11 parses a "pkcs11:module=...;token=...;object=...;pin=..." URI
+// (RFC 7512-flavoured, without pulling in a separate PKCS#11 URI parser).
+// The semicolon-separated attributes are read from the URI's opaque part;
+// any ?query parameters are accepted too, and the attributes override them.
+func openPKCS11(uri *url.URL) (CryptoBackend, error) {
+	q := uri.Query()
+	for _, attr := range strings.Split(uri.Opaque, ";") {
+		if kv := strings.SplitN(attr, "=", 2); len(kv) == 2 {
+			q.Set(kv[0], kv[1])
+		}
+	}
+	module := q.Get("module")
+	if module == "" {
+		return nil, fmt.Errorf("pkcs11: module path is required")
+	}
+
+	ctx := pkcs11.New(module)
+	if ctx == nil {
+		return nil, fmt.Errorf("pkcs11: failed to load module %q", module)
+	}
+	if err := ctx.Initialize(); err != nil {
+		return nil, fmt.Errorf("pkcs11: initialize: %w", err)
+	}
+
+	slot, err := findSlotByTokenLabel(ctx, q.Get("token"))
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: open session: %w", err)
+	}
+
+	if pin := q.Get("pin"); pin != "" {
+		if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil {
+			return nil, fmt.Errorf("pkcs11: login: %w", err)
+		}
+	}
+
+	keyLabel := q.Get("object")
+	handle, err := findObjectByLabel(ctx, session, pkcs11.CKO_PRIVATE_KEY, keyLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := &pkcs11Backend{ctx: ctx, session: session, keyLabel: keyLabel, privateKeyHandle: handle}
+
+	if certHandle, err := findObjectByLabel(ctx, session, pkcs11.CKO_CERTIFICATE, keyLabel); err == nil {
+		attrs, err := ctx.GetAttributeValue(session, certHandle, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+		})
+		if err == nil && len(attrs) == 1 {
+			if cert, err := x509.ParseCertificate(attrs[0].Value); err == nil {
+				backend.cert = cert
+				backend.pub = cert.PublicKey
+			}
+		}
+	}
+
+	return backend, nil
+}
+
+// findObjectByLabel looks up the single PKCS#11 object of the given class
+// (e.g. CKO_PRIVATE_KEY, CKO_CERTIFICATE) whose CKA_LABEL matches label.
+func findObjectByLabel(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, class uint, label string) (pkcs11.ObjectHandle, error) {
+	tmpl := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+
+	if err := ctx.FindObjectsInit(session, tmpl); err != nil {
+		return 0, fmt.Errorf("pkcs11: find objects init: %w", err)
+	}
+	defer ctx.FindObjectsFinal(session)
+
+	handles, _, err := ctx.FindObjects(session, 1)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: find objects: %w", err)
+	}
+	if len(handles) == 0 {
+		return 0, fmt.Errorf("pkcs11: no object with class %d and label %q", class, label)
+	}
+
+	return handles[0], nil
+}
+
+func findSlotByTokenLabel(ctx *pkcs11.Ctx, label string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: list slots: %w", err)
+	}
+
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if info.Label == label {
+			return slot, nil
+		}
+	}
+
+	return 0, fmt.Errorf("pkcs11: no token found with label %q (checked %d slots)", label, len(slots))
+}
+
+// Signer implements CryptoBackend.
+func (b *pkcs11Backend) Signer() (crypto.Signer, error) {
+	return &pkcs11Signer{backend: b}, nil
+}
+
+// Certificate implements CryptoBackend.
+func (b *pkcs11Backend) Certificate() (*x509.Certificate, error) {
+	if b.cert != nil {
+		return b.cert, nil
+	}
+	return nil, fmt.Errorf("pkcs11: no certificate object found for key %q", b.keyLabel)
+}
+
+// pkcs11Signer adapts a PKCS#11 private key object to crypto.Signer,
+// delegating the Sign operation to the token via C_Sign.
+type pkcs11Signer struct {
+	backend *pkcs11Backend
+}
+
+func (s *pkcs11Signer) Public() crypto.PublicKey {
+	return s.backend.pub
+}
+
+func (s *pkcs11Signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var mechanism []*pkcs11.Mechanism
+	data := digest
+
+	switch s.backend.pub.(type) {
+	case *ecdsa.PublicKey:
+		// CKM_ECDSA signs the bare digest directly; there is no
+		// RSA-style DigestInfo wrapper for ECDSA.
+		mechanism = []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}
+	case *rsa.PublicKey:
+		// CKM_RSA_PKCS expects the DER-encoded DigestInfo that
+		// crypto/rsa's own PKCS#1 v1.5 signer would build, not the
+		// bare hash - the token has no idea which hash produced
+		// digest otherwise and can't prepend the right prefix itself.
+		prefix, err := digestInfoPrefix(opts.HashFunc())
+		if err != nil {
+			return nil, fmt.Errorf("pkcs11: %w", err)
+		}
+		data = append(prefix, digest...)
+		mechanism = []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS, nil)}
+	default:
+		return nil, fmt.Errorf("pkcs11: unsupported key type %T for key %q", s.backend.pub, s.backend.keyLabel)
+	}
+
+	if err := s.backend.ctx.SignInit(s.backend.session, mechanism, s.backend.privateKeyHandle); err != nil {
+		return nil, fmt.Errorf("pkcs11: sign init: %w", err)
+	}
+	sig, err := s.backend.ctx.Sign(s.backend.session, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := s.backend.pub.(*ecdsa.PublicKey); ok {
+		// CKM_ECDSA returns r||s; crypto.Signer callers expect the
+		// ASN.1 DER form.
+		half := len(sig) / 2
+		return asn1.Marshal(struct{ R, S *big.Int }{
+			new(big.Int).SetBytes(sig[:half]),
+			new(big.Int).SetBytes(sig[half:]),
+		})
+	}
+	return sig, nil
+}
+
+// digestInfoPrefixes holds the DER encoding of the DigestAlgorithm
+// portion of a PKCS#1 v1.5 DigestInfo for each hash cfssl signs with,
+// per RFC 8017 §9.2 note 1. Appending the matching prefix to the raw
+// digest reproduces what crypto/rsa.SignPKCS1v15 sends to the RSA
+// primitive, which is what CKM_RSA_PKCS expects as its input.
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA224: {0x30, 0x2d, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x04, 0x05, 0x00, 0x04, 0x1c},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+func digestInfoPrefix(hash crypto.Hash) ([]byte, error) {
+	prefix, ok := digestInfoPrefixes[hash]
+	if !ok {
+		return nil, fmt.Errorf("unsupported hash algorithm %v for RSA PKCS#1 v1.5 signing", hash)
+	}
+	out := make([]byte, len(prefix))
+	copy(out, prefix)
+	return out, nil
+}
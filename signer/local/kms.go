@@ -0,0 +1,48 @@
+package local
+
+import (
+	"github.com/cloudflare/cfssl/config"
+	cferr "github.com/cloudflare/cfssl/errors"
+	"github.com/cloudflare/cfssl/signer"
+	"github.com/cloudflare/cfssl/signer/kms"
+)
+
+// NewSignerFromBackend builds a Signer whose private key operations are
+// delegated to backend, so the raw key bytes never need to be read into
+// this process. The certificate is taken from the backend as well, and
+// NewSignerFromBackend fails if the backend has none: only a backend that
+// stores one (as the bundled PKCS#11 implementation can) works here, not
+// the AWS/GCP KMS and Azure Key Vault backends, which only hold keys.
+func NewSignerFromBackend(backend kms.CryptoBackend, policy *config.Signing) (*Signer, error) {
+	priv, err := backend.Signer()
+	if err != nil {
+		return nil, cferr.New(cferr.PrivateKeyError, cferr.Unknown)
+	}
+
+	cert, err := backend.Certificate()
+	if err != nil {
+		return nil, cferr.New(cferr.CertificateError, cferr.Unknown)
+	}
+
+	if policy == nil {
+		policy = &config.Signing{
+			Profiles: nil,
+			Default:  config.DefaultConfig(),
+		}
+	}
+
+	return &Signer{cert, priv, policy, signer.DefaultSigAlgo(priv)}, nil
+}
+
+// NewSignerFromURI builds a Signer from a backend URI such as
+// "pkcs11:token=ca;object=root-key" or
+// "awskms:///arn:aws:kms:us-east-1:111111111111:key/...", dispatching to
+// whichever kms backend is registered for the URI's scheme.
+func NewSignerFromURI(uri string, policy *config.Signing) (*Signer, error) {
+	backend, err := kms.Open(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSignerFromBackend(backend, policy)
+}
@@ -0,0 +1,130 @@
+package local
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"math/big"
+	"time"
+
+	"github.com/cloudflare/cfssl/csr"
+	cferr "github.com/cloudflare/cfssl/errors"
+	"github.com/cloudflare/cfssl/helpers"
+	"github.com/cloudflare/cfssl/log"
+)
+
+// defaultRenewalValidity is the validity period of every certificate
+// RenewCA and Rekey issue; nothing in req changes it.
+const defaultRenewalValidity = 30 * 365 * 24 * time.Hour
+
+// serialNumberLimit bounds the random serial numbers minted for renewed
+// and rekeyed CA certificates, matching the 128-bit range initca uses.
+var serialNumberLimit = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// RenewCA reissues s's CA certificate, keeping the same key, subject and
+// key usage, but with a fresh validity period. This is the same pattern
+// initca.NewFromSigner uses to turn an existing private key into a
+// self-signed certificate, applied to a CA that is already in service.
+//
+// The subject is carried forward from s's CA certificate, with its Common
+// Name replaced by req.CN when that is set; req's other subject fields are
+// not used, and its KeyRequest is ignored since the existing key is reused.
+func (s *Signer) RenewCA(req *csr.CertificateRequest) ([]byte, error) {
+	if s.ca == nil {
+		return nil, cferr.New(cferr.PolicyError, cferr.InvalidPolicy)
+	}
+
+	tpl, err := s.caTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, s.priv.Public(), s.priv)
+	if err != nil {
+		return nil, cferr.New(cferr.CertificateError, cferr.Unknown)
+	}
+
+	log.Infof("renewed CA certificate, serial=%s, notAfter=%s", tpl.SerialNumber, tpl.NotAfter)
+	return helpers.EncodeCertificatePEM(&x509.Certificate{Raw: der}), nil
+}
+
+// Rekey generates a brand new key per req.KeyRequest, issues a fresh
+// self-signed CA certificate for it, and cross-signs the new public key
+// with the *old* CA key to produce a bridge certificate: a
+// certificate for the new key, signed by the old key, so relying parties
+// who still only trust the old CA can validate a chain through it while
+// they pick up the new root out of band.
+//
+// The returned cert is the bridge certificate followed by the new
+// self-signed CA certificate, PEM-concatenated in that order.
+func (s *Signer) Rekey(req *csr.CertificateRequest) (newKey, newCert []byte, err error) {
+	if s.ca == nil {
+		return nil, nil, cferr.New(cferr.PolicyError, cferr.InvalidPolicy)
+	}
+	if req.KeyRequest == nil {
+		return nil, nil, cferr.New(cferr.PolicyError, cferr.InvalidPolicy)
+	}
+
+	priv, err := req.KeyRequest.Generate()
+	if err != nil {
+		return nil, nil, cferr.New(cferr.PrivateKeyError, cferr.Unknown)
+	}
+
+	newKey, err = helpers.EncodePrivateKeyPEM(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tpl, err := s.caTemplate(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, nil, cferr.New(cferr.PrivateKeyError, cferr.Unknown)
+	}
+
+	selfSignedDER, err := x509.CreateCertificate(rand.Reader, tpl, tpl, signer.Public(), signer)
+	if err != nil {
+		return nil, nil, cferr.New(cferr.CertificateError, cferr.Unknown)
+	}
+
+	bridgeTpl := *tpl
+	bridgeDER, err := x509.CreateCertificate(rand.Reader, &bridgeTpl, s.ca, signer.Public(), s.priv)
+	if err != nil {
+		return nil, nil, cferr.New(cferr.CertificateError, cferr.Unknown)
+	}
+
+	bridgePEM := helpers.EncodeCertificatePEM(&x509.Certificate{Raw: bridgeDER})
+	selfSignedPEM := helpers.EncodeCertificatePEM(&x509.Certificate{Raw: selfSignedDER})
+
+	log.Infof("rekeyed CA, new subject key issued and cross-signed as a bridge certificate")
+	return newKey, append(bridgePEM, selfSignedPEM...), nil
+}
+
+// caTemplate builds an unsigned CA certificate template carrying s.ca's
+// subject and key usage, extended to a fresh validity period.
+func (s *Signer) caTemplate(req *csr.CertificateRequest) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, cferr.New(cferr.CertificateError, cferr.Unknown)
+	}
+
+	subject := s.ca.Subject
+	if req != nil && req.CN != "" {
+		subject.CommonName = req.CN
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               subject,
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(defaultRenewalValidity),
+		KeyUsage:              s.ca.KeyUsage,
+		ExtKeyUsage:           s.ca.ExtKeyUsage,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package local
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/cfssl/csr"
+	"github.com/cloudflare/cfssl/helpers"
+)
+
+func TestRenewCAExtendsValidity(t *testing.T) {
+	s := newTestSigner(t)
+
+	renewed, err := s.RenewCA(&csr.CertificateRequest{CN: s.ca.Subject.CommonName})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := helpers.ParseCertificatePEM(renewed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cert.NotAfter.After(s.ca.NotAfter) {
+		t.Fatalf("expected renewed CA to have a later expiry than %s, got %s", s.ca.NotAfter, cert.NotAfter)
+	}
+	if !reflect.DeepEqual(cert.PublicKey, s.ca.PublicKey) {
+		t.Fatal("expected renewed CA to keep the same public key")
+	}
+}
+
+func TestRekeyRSAToECDSA(t *testing.T) {
+	s := newTestSigner(t)
+
+	newKey, newCert, err := s.Rekey(&csr.CertificateRequest{
+		CN:         s.ca.Subject.CommonName,
+		KeyRequest: &csr.BasicKeyRequest{A: "ecdsa", S: 256},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	priv, err := helpers.ParsePrivateKeyPEM(newKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certs, err := helpers.ParseCertificatesPEM(newCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("expected a bridge certificate and a self-signed certificate, got %d", len(certs))
+	}
+
+	bridge, selfSigned := certs[0], certs[1]
+
+	if err := bridge.CheckSignatureFrom(s.ca); err != nil {
+		t.Fatalf("bridge certificate does not chain to the old CA: %v", err)
+	}
+
+	if selfSigned.NotAfter.Before(time.Now().Add(24 * time.Hour)) {
+		t.Fatal("expected self-signed certificate to have an extended validity period")
+	}
+
+	if priv == nil {
+		t.Fatal("expected the rekeyed private key to parse")
+	}
+}